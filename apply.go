@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/plan"
+)
+
+// runApply is the entry point of the apply command, it runs each step of a
+// reviewed plan against the organization, asking for confirmation before each
+// one unless told not to. It returns the exit code of the command.
+func runApply(args []string) int {
+	// STAGE 1: Sort out the input flags
+	flags := flag.NewFlagSet("apply", flag.ExitOnError)
+	yesPtr := flags.Bool("yes", false, "Run every step without asking for confirmation")
+	maxAttemptsPtr := flags.Int("max-attempts", generation.DefaultRetryPolicy.MaxAttempts, "The maximum number of times to attempt an AWS API call that is being throttled")
+	retryBaseDelayPtr := flags.Duration("retry-base-delay", generation.DefaultRetryPolicy.BaseDelay, "The base delay for the exponential backoff between throttled AWS API calls")
+	retryMaxDelayPtr := flags.Duration("retry-max-delay", generation.DefaultRetryPolicy.MaxDelay, "The maximum delay between throttled AWS API calls")
+	journalPtr := flags.String("journal", "", "The file that records the completed steps so a failed run can be resumed, defaults to the plan file with .journal added")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aws-organizations-visualiser apply [flags] plan.json")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+	journalFile := *journalPtr
+	if journalFile == "" {
+		journalFile = flags.Arg(0) + ".journal"
+	}
+
+	// STAGE 2: Set up the logging
+	ll := os.Getenv("LOGS_ENABLED")
+	setupLogging(ll)
+	logs.Println("Journal file:", journalFile)
+
+	// STAGE 3: Read the plan and the progress of any earlier runs
+	p, err := plan.ReadFromFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println("Error reading plan")
+		logs.Println(err)
+		return 1
+	}
+	journal, err := plan.OpenJournal(journalFile)
+	if err != nil {
+		fmt.Println("Error reading journal")
+		logs.Println(err)
+		return 1
+	}
+	fmt.Print(p.Report())
+	if len(p.Steps) == 0 {
+		return 0
+	}
+	if len(journal.Entries) > 0 {
+		fmt.Printf("Resuming, %d step(s) already completed\n", len(journal.Entries))
+	}
+
+	// STAGE 4: Run the steps of the plan against the organization
+	ctx, cfg, err := checkPermissions()
+	if err != nil {
+		fmt.Println("Error checking permissions")
+		logs.Println(err)
+		return 1
+	}
+	opts := plan.ApplyOptions{
+		Journal: journal,
+		Retry: generation.RetryPolicy{
+			MaxAttempts: *maxAttemptsPtr,
+			BaseDelay:   *retryBaseDelayPtr,
+			MaxDelay:    *retryMaxDelayPtr,
+		},
+		Completed: func(position int, step plan.Step) {
+			fmt.Printf("  %d. %s: done\n", position, step)
+		},
+	}
+	if !*yesPtr {
+		opts.Confirm = confirmStep(os.Stdin)
+	}
+	err = plan.Apply(ctx, cfg, p, opts)
+	if errors.Is(err, plan.ErrDeclined) {
+		fmt.Println("Stopped, run the command again to resume from this step")
+		return 1
+	}
+	if err != nil {
+		fmt.Println("Error applying plan, run the command again to resume from the failed step")
+		fmt.Println(err)
+		return 1
+	}
+	fmt.Println("Plan applied")
+	return 0
+}
+
+// confirmStep returns a function that asks for confirmation before each step
+// is run, reading the answers from the given reader. Answering "a" confirms
+// the step and every step after it.
+func confirmStep(in io.Reader) func(position int, step plan.Step) bool {
+	reader := bufio.NewReader(in)
+	all := false
+	return func(position int, step plan.Step) bool {
+		if all {
+			return true
+		}
+		fmt.Printf("Run step %d, %s? [y/N/a] ", position, step)
+		answer, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true
+		case "a", "all":
+			all = true
+			return true
+		}
+		return false
+	}
+}
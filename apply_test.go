@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/plan"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfirmStep tests that each step is confirmed from the answers read, and
+// that answering "a" confirms every step after it.
+func TestConfirmStep(t *testing.T) {
+	step := plan.Step{Action: plan.ActionDeleteOU, Id: "ou-2", Name: "Legacy", Path: "Root/Legacy"}
+	confirm := confirmStep(strings.NewReader("y\nn\n\nA\n"))
+
+	var answers []bool
+	output := captureOutput(func() {
+		for i := 1; i <= 5; i++ {
+			answers = append(answers, confirm(i, step))
+		}
+	})
+	require.Equal(t, []bool{true, false, false, true, true}, answers)
+	expectedOutput := "" +
+		"Run step 1, delete OU Root/Legacy (ou-2)? [y/N/a] " +
+		"Run step 2, delete OU Root/Legacy (ou-2)? [y/N/a] " +
+		"Run step 3, delete OU Root/Legacy (ou-2)? [y/N/a] " +
+		"Run step 4, delete OU Root/Legacy (ou-2)? [y/N/a] "
+	require.Equal(t, expectedOutput, output)
+
+	// Running out of answers declines the step.
+	confirm = confirmStep(strings.NewReader(""))
+	captureOutput(func() {
+		require.False(t, confirm(1, step))
+	})
+}
+
+// TestRunApplyNoChanges tests that the apply command doesn't need access to
+// AWS if the plan has no steps, and reports a plan it can't read.
+func TestRunApplyNoChanges(t *testing.T) {
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(planFile, []byte(`{"steps":[]}`), 0o600))
+
+	var code int
+	output := captureOutput(func() {
+		code = runApply([]string{planFile})
+	})
+	require.Equal(t, "No changes needed\n", output)
+	require.Equal(t, 0, code)
+
+	output = captureOutput(func() {
+		code = runApply([]string{filepath.Join(t.TempDir(), "missing.json")})
+	})
+	require.Equal(t, "Error reading plan\n", output)
+	require.Equal(t, 1, code)
+}
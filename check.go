@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/json"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/layout"
+)
+
+// The exit codes of the check command, so that it can be used to gate
+// pipelines on the organization matching its layout.
+const (
+	checkExitOK    = 0
+	checkExitDrift = 1
+	checkExitError = 2
+)
+
+// runCheck is the entry point of the check command, it compares the
+// organization, either live or from a snapshot, with the desired layout and
+// outputs any drift. It returns the exit code of the command.
+func runCheck(args []string) int {
+	// STAGE 1: Sort out the input flags
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	inputPtr := flags.String("input", "", "A JSON file previously written by this application to check instead of querying AWS")
+	jsonPtr := flags.Bool("include-json", true, "Include the JSON list of differences from the layout in the output")
+	visualPtr := flags.Bool("include-visual", true, "Include the human readable report of differences from the layout in the output")
+	outputPtr := flags.String("o", "check.json", "The output file for the JSON list of differences from the layout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aws-organizations-visualiser check [flags] layout.yaml")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return checkExitError
+	}
+
+	// STAGE 2: Set up the logging
+	ll := os.Getenv("LOGS_ENABLED")
+	setupLogging(ll)
+
+	// STAGE 3: Get the desired layout and the organization
+	desired, err := layout.ReadFromFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println("Error reading layout")
+		logs.Println(err)
+		return checkExitError
+	}
+	org, err := getOrganization(*inputPtr, generation.Options{})
+	if err != nil {
+		return checkExitError
+	}
+
+	// STAGE 4: Check the organization against the layout and output the drift
+	findings := layout.Check(desired, org.Root)
+	if *visualPtr {
+		fmt.Print(findings.Report())
+	}
+	if *jsonPtr {
+		jsonFindings, err := findings.ToJSON()
+		if err != nil {
+			fmt.Println("Error generating JSON")
+			logs.Println(err)
+			return checkExitError
+		}
+		err = json.OutputToFile(jsonFindings, *outputPtr)
+		if err != nil {
+			fmt.Println("Error outputting JSON to file")
+			logs.Println(err)
+			return checkExitError
+		}
+	}
+	if len(findings) > 0 {
+		return checkExitDrift
+	}
+	return checkExitOK
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunCheck tests that the check command reports drift from the layout
+// with a non-zero exit code.
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	snapshotFile := filepath.Join(dir, "snapshot.json")
+	layoutFile := filepath.Join(dir, "layout.yaml")
+	require.NoError(t, os.WriteFile(snapshotFile, []byte(`{"id":"o-1234","root":{"id":"r-1234","name":"Root","children":[{"id":"ou-1","name":"Workloads","children":[],"accounts":[]}],"accounts":[]}}`), 0o600))
+
+	// The organization matches the layout.
+	require.NoError(t, os.WriteFile(layoutFile, []byte("root:\n  ous:\n    - name: Workloads\n"), 0o600))
+	var code int
+	output := captureOutput(func() {
+		code = runCheck([]string{"-include-json=false", "-input", snapshotFile, layoutFile})
+	})
+	require.Equal(t, "No drift from the layout\n", output)
+	require.Equal(t, checkExitOK, code)
+
+	// The organization has drifted from the layout.
+	require.NoError(t, os.WriteFile(layoutFile, []byte("root:\n  ous:\n    - name: Sandbox\n"), 0o600))
+	outputFile := filepath.Join(dir, "check.json")
+	output = captureOutput(func() {
+		code = runCheck([]string{"-o", outputFile, "-input", snapshotFile, layoutFile})
+	})
+	require.Equal(t, "2 difference(s) from the layout:\n  - missing OU Root/Sandbox\n  - unexpected OU Root/Workloads (ou-1)\n", output)
+	require.Equal(t, checkExitDrift, code)
+	jsonFindings, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"type":"MISSING_OU","path":"Root/Sandbox"},{"type":"UNEXPECTED_OU","path":"Root/Workloads","id":"ou-1"}]`, string(jsonFindings))
+
+	// The check couldn't be run.
+	output = captureOutput(func() {
+		code = runCheck([]string{"-input", snapshotFile, filepath.Join(dir, "missing.yaml")})
+	})
+	require.Equal(t, "Error reading layout\n", output)
+	require.Equal(t, checkExitError, code)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/diff"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/json"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+)
+
+// runDiff is the entry point of the diff command, it compares an old snapshot
+// with either a new snapshot or the live organization and outputs the changes
+// between them.
+func runDiff(args []string) {
+	// STAGE 1: Sort out the input flags
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	jsonPtr := flags.Bool("include-json", true, "Include the JSON list of changes in the output")
+	visualPtr := flags.Bool("include-visual", true, "Include the human readable report of changes in the output")
+	outputPtr := flags.String("o", "diff.json", "The output file for the JSON list of changes")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aws-organizations-visualiser diff [flags] old.json [new.json]")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		return
+	}
+
+	// STAGE 2: Set up the logging
+	ll := os.Getenv("LOGS_ENABLED")
+	setupLogging(ll)
+
+	// STAGE 3: Get the old and new states of the organization, using the live
+	// organization if no new snapshot is given
+	before, err := json.ReadFromFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println("Error reading old snapshot")
+		logs.Println(err)
+		return
+	}
+	after, err := getOrganization(flags.Arg(1), generation.Options{})
+	if err != nil {
+		return
+	}
+
+	// STAGE 4: Compare the states and output the changes
+	changes := diff.Compare(before.Root, after.Root)
+	if *visualPtr {
+		fmt.Print(changes.Report())
+	}
+	if *jsonPtr {
+		jsonChanges, err := changes.ToJSON()
+		if err != nil {
+			fmt.Println("Error generating JSON")
+			logs.Println(err)
+			return
+		}
+		err = json.OutputToFile(jsonChanges, *outputPtr)
+		if err != nil {
+			fmt.Println("Error outputting JSON to file")
+			logs.Println(err)
+			return
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// # Diff
+//
+// This package contains the code for comparing two states of an AWS
+// Organization. It matches the OUs and accounts in the trees generated by the
+// generation package by their IDs and lists what has changed between them.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// ChangeType is the kind of change made to an OU or account.
+type ChangeType string
+
+const (
+	OUAdded              ChangeType = "OU_ADDED"
+	OURemoved            ChangeType = "OU_REMOVED"
+	OURenamed            ChangeType = "OU_RENAMED"
+	OUMoved              ChangeType = "OU_MOVED"
+	AccountAdded         ChangeType = "ACCOUNT_ADDED"
+	AccountRemoved       ChangeType = "ACCOUNT_REMOVED"
+	AccountRenamed       ChangeType = "ACCOUNT_RENAMED"
+	AccountMoved         ChangeType = "ACCOUNT_MOVED"
+	AccountStatusChanged ChangeType = "ACCOUNT_STATUS_CHANGED"
+)
+
+// --- Change ------------------------------------------------------------------
+// Change is a struct that represents a single difference between the old and
+// new state of an OU or account.
+type Change struct {
+	Type ChangeType `json:"type"`
+	Id   string     `json:"id"`
+	// Name is the name of the OU or account in the new state, or in the old
+	// state if it has been removed.
+	Name string `json:"name"`
+	// Path is the path of the parent OU, in the new state unless the OU or
+	// account has been removed.
+	Path string `json:"path"`
+	// From and To are the old and new values of what changed, the name for
+	// renames, the parent path for moves and the status for status changes.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// String returns a human readable description of the change.
+func (c Change) String() string {
+	kind := "Account"
+	if strings.HasPrefix(string(c.Type), "OU_") {
+		kind = "OU"
+	}
+
+	switch c.Type {
+	case OUAdded, AccountAdded:
+		return fmt.Sprintf("+ %s %s (%s) added to %s", kind, c.Name, c.Id, c.Path)
+	case OURemoved, AccountRemoved:
+		return fmt.Sprintf("- %s %s (%s) removed from %s", kind, c.Name, c.Id, c.Path)
+	case OURenamed, AccountRenamed:
+		return fmt.Sprintf("~ %s %s (%s) renamed from %s", kind, c.Name, c.Id, c.From)
+	case OUMoved, AccountMoved:
+		return fmt.Sprintf("~ %s %s (%s) moved from %s to %s", kind, c.Name, c.Id, c.From, c.To)
+	case AccountStatusChanged:
+		return fmt.Sprintf("~ %s %s (%s) status changed from %s to %s", kind, c.Name, c.Id, c.From, c.To)
+	}
+	return fmt.Sprintf("? %s %s (%s) %s", kind, c.Name, c.Id, c.Type)
+}
+
+// --- Changes -----------------------------------------------------------------
+// Changes is the list of every change between two states of an organization.
+type Changes []Change
+
+// ToJSON returns a JSON representation of the changes.
+func (c Changes) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// Report returns a human readable report of the changes, one per line.
+func (c Changes) Report() string {
+	if len(c) == 0 {
+		return "No changes\n"
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "%d change(s):\n", len(c))
+	for _, change := range c {
+		fmt.Fprintf(&report, "  %s\n", change)
+	}
+	return report.String()
+}
+
+// node is an OU or account found in a tree, along with where it was found.
+type node struct {
+	name     string
+	parentId string
+	path     string
+	status   types.AccountStatus
+}
+
+// index is every OU and account in a tree in the order they were found, keyed
+// by their IDs.
+type index struct {
+	ous      []string
+	accounts []string
+	nodes    map[string]node
+}
+
+// newIndex walks the tree and indexes each of its OUs and accounts.
+func newIndex(root *generation.OU) index {
+	idx := index{nodes: make(map[string]node)}
+	if root == nil {
+		return idx
+	}
+
+	var walk func(ou *generation.OU, path string)
+	walk = func(ou *generation.OU, path string) {
+		for _, account := range ou.Accounts {
+			id := aws.ToString(account.Id)
+			idx.accounts = append(idx.accounts, id)
+			idx.nodes[id] = node{name: aws.ToString(account.Name), parentId: ou.Id, path: path, status: account.Status}
+		}
+		for _, child := range ou.Children {
+			idx.ous = append(idx.ous, child.Id)
+			idx.nodes[child.Id] = node{name: child.Name, parentId: ou.Id, path: path}
+			walk(child, path+"/"+child.Name)
+		}
+	}
+	walk(root, root.Name)
+	return idx
+}
+
+// Compare matches the OUs and accounts in the before and after trees by their
+// IDs and returns every change between them. The changes to OUs come first, then
+// the changes to accounts, each in the order they appear in the trees with
+// anything removed at the end.
+func Compare(before, after *generation.OU) Changes {
+	oldIdx, newIdx := newIndex(before), newIndex(after)
+	changes := make(Changes, 0)
+	changes = append(changes, compareIds(oldIdx, newIdx, oldIdx.ous, newIdx.ous, OUAdded, OURemoved, OURenamed, OUMoved)...)
+	changes = append(changes, compareIds(oldIdx, newIdx, oldIdx.accounts, newIdx.accounts, AccountAdded, AccountRemoved, AccountRenamed, AccountMoved)...)
+	return changes
+}
+
+// compareIds compares the OUs or accounts with the given IDs in each index,
+// using the given change types for what is found.
+func compareIds(oldIdx, newIdx index, oldIds, newIds []string, added, removed, renamed, moved ChangeType) Changes {
+	changes := make(Changes, 0)
+	for _, id := range newIds {
+		after := newIdx.nodes[id]
+		before, ok := oldIdx.nodes[id]
+		if !ok {
+			changes = append(changes, Change{Type: added, Id: id, Name: after.name, Path: after.path})
+			continue
+		}
+		if before.name != after.name {
+			changes = append(changes, Change{Type: renamed, Id: id, Name: after.name, Path: after.path, From: before.name, To: after.name})
+		}
+		// Compare the parents rather than the paths, so renaming an OU doesn't
+		// move everything below it.
+		if before.parentId != after.parentId {
+			changes = append(changes, Change{Type: moved, Id: id, Name: after.name, Path: after.path, From: before.path, To: after.path})
+		}
+		if before.status != after.status {
+			changes = append(changes, Change{Type: AccountStatusChanged, Id: id, Name: after.name, Path: after.path, From: string(before.status), To: string(after.status)})
+		}
+	}
+	for _, id := range oldIds {
+		if _, ok := newIdx.nodes[id]; !ok {
+			before := oldIdx.nodes[id]
+			changes = append(changes, Change{Type: removed, Id: id, Name: before.name, Path: before.path})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompare tests that every kind of change is found between two trees.
+func TestCompare(t *testing.T) {
+	old := &generation.OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+		},
+		Children: []*generation.OU{
+			{
+				Id:   "ou-1",
+				Name: "Workloads",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Prod"), Status: types.AccountStatusActive}},
+					{Account: types.Account{Id: aws.String("333333333333"), Name: aws.String("Dev"), Status: types.AccountStatusActive}},
+				},
+			},
+			{
+				Id:   "ou-2",
+				Name: "Legacy",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("444444444444"), Name: aws.String("Old"), Status: types.AccountStatusActive}},
+				},
+			},
+		},
+	}
+	updated := &generation.OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+		},
+		Children: []*generation.OU{
+			{
+				Id:   "ou-1",
+				Name: "Apps",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Production"), Status: types.AccountStatusSuspended}},
+				},
+				Children: []*generation.OU{
+					{
+						Id:   "ou-3",
+						Name: "Sandbox",
+						Accounts: []generation.Account{
+							{Account: types.Account{Id: aws.String("333333333333"), Name: aws.String("Dev"), Status: types.AccountStatusActive}},
+							{Account: types.Account{Id: aws.String("555555555555"), Name: aws.String("New"), Status: types.AccountStatusActive}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	changes := Compare(old, updated)
+	require.Equal(t, Changes{
+		{Type: OURenamed, Id: "ou-1", Name: "Apps", Path: "Root", From: "Workloads", To: "Apps"},
+		{Type: OUAdded, Id: "ou-3", Name: "Sandbox", Path: "Root/Apps"},
+		{Type: OURemoved, Id: "ou-2", Name: "Legacy", Path: "Root"},
+		{Type: AccountRenamed, Id: "222222222222", Name: "Production", Path: "Root/Apps", From: "Prod", To: "Production"},
+		{Type: AccountStatusChanged, Id: "222222222222", Name: "Production", Path: "Root/Apps", From: "ACTIVE", To: "SUSPENDED"},
+		{Type: AccountMoved, Id: "333333333333", Name: "Dev", Path: "Root/Apps/Sandbox", From: "Root/Workloads", To: "Root/Apps/Sandbox"},
+		{Type: AccountAdded, Id: "555555555555", Name: "New", Path: "Root/Apps/Sandbox"},
+		{Type: AccountRemoved, Id: "444444444444", Name: "Old", Path: "Root/Legacy"},
+	}, changes)
+
+	// Comparing a tree with itself finds nothing.
+	require.Empty(t, Compare(updated, updated))
+}
+
+// TestReport tests the human readable report of the changes.
+func TestReport(t *testing.T) {
+	require.Equal(t, "No changes\n", Changes{}.Report())
+
+	changes := Changes{
+		{Type: OUAdded, Id: "ou-3", Name: "Sandbox", Path: "Root/Apps"},
+		{Type: OURenamed, Id: "ou-1", Name: "Apps", Path: "Root", From: "Workloads", To: "Apps"},
+		{Type: AccountMoved, Id: "333333333333", Name: "Dev", From: "Root/Workloads", To: "Root/Apps"},
+		{Type: AccountStatusChanged, Id: "222222222222", Name: "Prod", From: "ACTIVE", To: "SUSPENDED"},
+		{Type: AccountRemoved, Id: "444444444444", Name: "Old", Path: "Root/Legacy"},
+	}
+	expectedReport := "" +
+		"5 change(s):\n" +
+		"  + OU Sandbox (ou-3) added to Root/Apps\n" +
+		"  ~ OU Apps (ou-1) renamed from Workloads\n" +
+		"  ~ Account Dev (333333333333) moved from Root/Workloads to Root/Apps\n" +
+		"  ~ Account Prod (222222222222) status changed from ACTIVE to SUSPENDED\n" +
+		"  - Account Old (444444444444) removed from Root/Legacy\n"
+	require.Equal(t, expectedReport, changes.Report())
+}
+
+// TestChangesToJSON tests the JSON list of changes.
+func TestChangesToJSON(t *testing.T) {
+	changes := Changes{
+		{Type: AccountAdded, Id: "555555555555", Name: "New", Path: "Root"},
+	}
+	jsonChanges, err := changes.ToJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"type":"ACCOUNT_ADDED","id":"555555555555","name":"New","path":"Root"}]`, string(jsonChanges))
+}
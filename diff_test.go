@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunDiff tests that the diff command compares two snapshots and outputs
+// both the report and the JSON list of changes.
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.json")
+	newFile := filepath.Join(dir, "new.json")
+	outputFile := filepath.Join(dir, "diff.json")
+	require.NoError(t, os.WriteFile(oldFile, []byte(`{"id":"o-1234","root":{"id":"r-1234","name":"Root","children":[{"id":"ou-1","name":"Workloads","children":[],"accounts":[]}],"accounts":[]}}`), 0o600))
+	require.NoError(t, os.WriteFile(newFile, []byte(`{"id":"o-1234","root":{"id":"r-1234","name":"Root","children":[],"accounts":[]}}`), 0o600))
+
+	output := captureOutput(func() {
+		runDiff([]string{"-o", outputFile, oldFile, newFile})
+	})
+	require.Equal(t, "1 change(s):\n  - OU Workloads (ou-1) removed from Root\n", output)
+
+	jsonChanges, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"type":"OU_REMOVED","id":"ou-1","name":"Workloads","path":"Root"}]`, string(jsonChanges))
+
+	// A missing snapshot is reported.
+	output = captureOutput(func() {
+		runDiff([]string{"-include-json=false", filepath.Join(dir, "missing.json"), newFile})
+	})
+	require.Equal(t, "Error reading old snapshot\n", output)
+}
@@ -6,15 +6,138 @@
 package cli
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
 )
 
-// Display is a function that takes in the tree structure and displays it in a
-// visually appealing way in the CLI.
-func Display(tree *generation.OU) {
-	// TODO: use flags to determine whether to display detailed output or not
+// Options holds the settings that control what is shown in the CLI display,
+// both by Display and when rendering to a writer with Render.
+type Options struct {
+	// Detailed shows the number of accounts in each OU.
+	Detailed bool
+	// Totals adds the number of accounts in each OU and every OU below it to
+	// the detailed output, broken down by their status.
+	Totals bool
+	// TagKeys is the keys of the tags to show next to each name, in the order
+	// they are shown.
+	TagKeys []string
+	// ShowAccounts shows each account as a leaf below the OU it is in, with its
+	// ID and status.
+	ShowAccounts bool
+	// MaxDepth is the number of levels of OUs below the root to show, the OUs
+	// below that are summarised in a single line. Every level is shown if it
+	// is 0.
+	MaxDepth int
+	// Colour colours the management account, suspended accounts and empty OUs
+	// differently, see ColourSupported.
+	Colour bool
+	// Connectors is the set of strings used to draw the tree, it defaults to
+	// UnicodeConnectors.
+	Connectors Connectors
+
+	// managementAccountId is the ID of the management account, set by Display
+	// so that it can be coloured.
+	managementAccountId string
+}
+
+// Display is a function that takes in the organization and displays a header
+// with its details followed by the tree structure in a visually appealing way
+// in the CLI.
+func Display(org *generation.Organization, opts Options) {
 	// TODO: have different display options (e.g. tree, list, etc.)
-	// TODO: Include Accounts in the display if requested
-	detailed := true
-	displayTree(tree, detailed)
+	_ = Render(os.Stdout, org, opts)
+}
+
+// Render writes the same output as Display to the writer, so that the tree can
+// be embedded in other tools. It returns the first error from the writer.
+func Render(w io.Writer, org *generation.Organization, opts Options) error {
+	ew := &errWriter{w: w}
+	displayHeader(ew, org)
+	opts.managementAccountId = org.ManagementAccountId
+	displayTree(ew, org.Root, opts)
+	displayDelegatedAdministrators(ew, org)
+	return ew.err
+}
+
+// RenderString returns the same output as Display as a string.
+func RenderString(org *generation.Organization, opts Options) string {
+	var output strings.Builder
+	_ = Render(&output, org, opts)
+	return output.String()
+}
+
+// errWriter is a writer that keeps the first error returned by the writer it
+// wraps and skips every write after it, so that the display functions don't
+// need to check the error of each line they print.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Write writes to the wrapped writer unless an earlier write failed.
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
+
+// displayHeader writes the details of the organization that the tree belongs
+// to, including which policy types are enabled on the root. Nothing is printed
+// if the details aren't known, as with snapshots that only hold the tree.
+func displayHeader(w io.Writer, org *generation.Organization) {
+	if org.Id == "" {
+		return
+	}
+
+	policyTypes := "none"
+	if len(org.EnabledPolicyTypes) > 0 {
+		enabled := make([]string, len(org.EnabledPolicyTypes))
+		for i, policyType := range org.EnabledPolicyTypes {
+			enabled[i] = fmt.Sprintf("%s (%s)", policyType.Type, policyType.Status)
+		}
+		policyTypes = strings.Join(enabled, ", ")
+	}
+
+	fmt.Fprintf(w, "Organization:       %s (%s)\n", org.Id, org.Arn)
+	fmt.Fprintf(w, "Management account: %s (%s)\n", org.ManagementAccountId, org.ManagementAccountEmail)
+	fmt.Fprintf(w, "Feature set:        %s\n", org.FeatureSet)
+	fmt.Fprintf(w, "Policy types:       %s\n", policyTypes)
+	fmt.Fprintln(w)
+}
+
+// displayDelegatedAdministrators writes the services with trusted access to the
+// organization and the accounts that are delegated administrators, it writes
+// nothing if they weren't fetched.
+func displayDelegatedAdministrators(w io.Writer, org *generation.Organization) {
+	if org.TrustedServices == nil && org.DelegatedAdministrators == nil {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Trusted service access:")
+	if len(org.TrustedServices) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, service := range org.TrustedServices {
+		fmt.Fprintf(w, "  - %s\n", service.ServicePrincipal)
+	}
+
+	fmt.Fprintln(w, "Delegated administrators:")
+	if len(org.DelegatedAdministrators) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, admin := range org.DelegatedAdministrators {
+		services := make([]string, len(admin.Services))
+		for i, service := range admin.Services {
+			services[i] = service.ServicePrincipal
+		}
+		fmt.Fprintf(w, "  - %s (%s): %s\n", admin.Name, admin.AccountId, strings.Join(services, ", "))
+	}
 }
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplay(t *testing.T) {
+	org := &generation.Organization{
+		Id:                     "o-1234",
+		Arn:                    "arn:aws:organizations::111111111111:organization/o-1234",
+		ManagementAccountId:    "111111111111",
+		ManagementAccountEmail: "management@example.com",
+		FeatureSet:             types.OrganizationFeatureSetAll,
+		EnabledPolicyTypes: []generation.PolicyTypeSummary{
+			{Type: types.PolicyTypeServiceControlPolicy, Status: types.PolicyTypeStatusEnabled},
+			{Type: types.PolicyTypeTagPolicy, Status: types.PolicyTypeStatusPendingEnable},
+		},
+		Root: &generation.OU{
+			Name: "Root",
+			Children: []*generation.OU{
+				{
+					Name: "TestOU",
+				},
+			},
+		},
+	}
+
+	// Display the header followed by the tree.
+	expectedOutput := "" +
+		"Organization:       o-1234 (arn:aws:organizations::111111111111:organization/o-1234)\n" +
+		"Management account: 111111111111 (management@example.com)\n" +
+		"Feature set:        ALL\n" +
+		"Policy types:       SERVICE_CONTROL_POLICY (ENABLED), TAG_POLICY (PENDING_ENABLE)\n" +
+		"\n" +
+		"└─┬─ Root (0)\n" +
+		"  └─── TestOU (0)\n"
+	output := captureOutput(func() {
+		Display(org, Options{Detailed: true})
+	})
+	require.Equal(t, expectedOutput, output, "Organization was not displayed correctly")
+
+	// Display "none" if no policy types are enabled.
+	org.EnabledPolicyTypes = nil
+	output = renderOutput(func(w io.Writer) {
+		displayHeader(w, org)
+	})
+	require.Contains(t, output, "Policy types:       none\n", "Policy types were not displayed correctly")
+
+	// Display no header if the details of the organization aren't known.
+	org.Id = ""
+	output = renderOutput(func(w io.Writer) {
+		displayHeader(w, org)
+	})
+	require.Equal(t, "", output, "Header was displayed without the organization details")
+}
+
+func TestRender(t *testing.T) {
+	org := &generation.Organization{
+		ManagementAccountId: "111111111111",
+		Root: &generation.OU{
+			Name: "Root",
+			Accounts: []generation.Account{
+				{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+			},
+		},
+	}
+	opts := Options{ShowAccounts: true, Colour: true, Connectors: ASCIIConnectors}
+
+	// Render the tree into the writer, passing on the management account.
+	expectedOutput := "" +
+		"`-+- Root\n" +
+		"  `--- \x1b[1;33mManagement\x1b[0m (111111111111) ACTIVE\n"
+	var buf bytes.Buffer
+	err := Render(&buf, org, opts)
+	require.NoError(t, err)
+	require.Equal(t, expectedOutput, buf.String(), "Organization was not rendered correctly")
+	require.Equal(t, expectedOutput, RenderString(org, opts), "Organization was not rendered correctly")
+
+	// Return the first error from the writer.
+	err = Render(failingWriter{}, org, opts)
+	require.EqualError(t, err, "write failed")
+}
+
+// failingWriter is a writer that fails every write.
+type failingWriter struct{}
+
+// Write returns an error without writing anything.
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestDisplayDelegatedAdministrators(t *testing.T) {
+	org := &generation.Organization{}
+
+	// Display nothing if the delegated administrators weren't fetched.
+	output := renderOutput(func(w io.Writer) {
+		displayDelegatedAdministrators(w, org)
+	})
+	require.Equal(t, "", output, "Delegated administrators were not displayed correctly")
+
+	// Display "none" if there aren't any.
+	org.TrustedServices = []generation.TrustedService{}
+	org.DelegatedAdministrators = []generation.DelegatedAdministrator{}
+	expectedOutput := "" +
+		"\n" +
+		"Trusted service access:\n" +
+		"  none\n" +
+		"Delegated administrators:\n" +
+		"  none\n"
+	output = renderOutput(func(w io.Writer) {
+		displayDelegatedAdministrators(w, org)
+	})
+	require.Equal(t, expectedOutput, output, "Delegated administrators were not displayed correctly")
+
+	// Display each service and admin.
+	org.TrustedServices = []generation.TrustedService{
+		{ServicePrincipal: "guardduty.amazonaws.com"},
+		{ServicePrincipal: "config.amazonaws.com"},
+	}
+	org.DelegatedAdministrators = []generation.DelegatedAdministrator{
+		{
+			AccountId: "222222222222",
+			Name:      "Security",
+			Services: []generation.DelegatedService{
+				{ServicePrincipal: "guardduty.amazonaws.com"},
+				{ServicePrincipal: "securityhub.amazonaws.com"},
+			},
+		},
+	}
+	expectedOutput = "" +
+		"\n" +
+		"Trusted service access:\n" +
+		"  - guardduty.amazonaws.com\n" +
+		"  - config.amazonaws.com\n" +
+		"Delegated administrators:\n" +
+		"  - Security (222222222222): guardduty.amazonaws.com, securityhub.amazonaws.com\n"
+	output = renderOutput(func(w io.Writer) {
+		displayDelegatedAdministrators(w, org)
+	})
+	require.Equal(t, expectedOutput, output, "Delegated administrators were not displayed correctly")
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// --- Connectors --------------------------------------------------------------
+// Connectors is the set of strings used to draw the lines of the tree. Each
+// fork is followed by the wordSpacer before the name of the Node.
+type Connectors struct {
+	Empty        string
+	Continue     string
+	Fork         string
+	ForkChild    string
+	EndFork      string
+	EndForkChild string
+}
+
+// UnicodeConnectors draws the tree with box-drawing characters, it is used
+// unless other connectors are chosen.
+var UnicodeConnectors = Connectors{
+	Empty:        emptySpacer,
+	Continue:     continueSpacer,
+	Fork:         fork,
+	ForkChild:    forkChild,
+	EndFork:      endFork,
+	EndForkChild: endForkChild,
+}
+
+// ASCIIConnectors draws the tree with plain ASCII characters, for terminals and
+// log systems that mangle the box-drawing characters.
+var ASCIIConnectors = Connectors{
+	Empty:        "  ",
+	Continue:     "| ",
+	Fork:         "|---" + wordSpacer,
+	ForkChild:    "|-+-" + wordSpacer,
+	EndFork:      "`---" + wordSpacer,
+	EndForkChild: "`-+-" + wordSpacer,
+}
+
+// translate returns the connector in this set that is used in place of the
+// given spacer or prefix from the tree struct, which is always built with the
+// Unicode connectors.
+func (c Connectors) translate(connector string) string {
+	if c == (Connectors{}) {
+		return connector
+	}
+	switch connector {
+	case emptySpacer:
+		return c.Empty
+	case continueSpacer:
+		return c.Continue
+	case fork:
+		return c.Fork
+	case forkChild:
+		return c.ForkChild
+	case endFork:
+		return c.EndFork
+	case endForkChild:
+		return c.EndForkChild
+	}
+	return connector
+}
+
+// --- Colours -----------------------------------------------------------------
+// ANSI escape codes used to colour the names in the tree.
+const (
+	colourReset      = "\x1b[0m"
+	colourManagement = "\x1b[1;33m"
+	colourSuspended  = "\x1b[31m"
+	colourEmpty      = "\x1b[2m"
+)
+
+// colourise wraps the text in the given colour if colours are enabled.
+func colourise(text, colour string, enabled bool) string {
+	if !enabled || colour == "" {
+		return text
+	}
+	return colour + text + colourReset
+}
+
+// accountColour returns the colour of an account, bold yellow for the
+// management account and red for accounts that are suspended or closing.
+func accountColour(id string, status types.AccountStatus, managementAccountId string) string {
+	if id != "" && id == managementAccountId {
+		return colourManagement
+	}
+	if status == types.AccountStatusSuspended || status == types.AccountStatusPendingClosure {
+		return colourSuspended
+	}
+	return ""
+}
+
+// ColourSupported returns whether colours should be used when writing to the
+// file. They are used only if the file is a terminal and the NO_COLOR
+// environment variable isn't set, see https://no-color.org.
+func ColourSupported(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testStyleOU is the tree displayed in the tests below.
+var testStyleOU = &generation.OU{
+	Name: "Root",
+	Accounts: []generation.Account{
+		{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+	},
+	Children: []*generation.OU{
+		{
+			Name: "Workloads",
+			Accounts: []generation.Account{
+				{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Prod"), Status: types.AccountStatusActive}},
+				{Account: types.Account{Id: aws.String("333333333333"), Name: aws.String("Legacy"), Status: types.AccountStatusSuspended}},
+			},
+			Children: []*generation.OU{
+				{Name: "Empty"},
+			},
+		},
+		{Name: "Sandbox"},
+	},
+}
+
+func TestDisplayTreeASCII(t *testing.T) {
+	expectedOutput := "" +
+		"`-+- Root\n" +
+		"  |--- Management (111111111111) ACTIVE\n" +
+		"  |-+- Workloads\n" +
+		"  | |--- Prod (222222222222) ACTIVE\n" +
+		"  | |--- Legacy (333333333333) SUSPENDED\n" +
+		"  | `--- Empty\n" +
+		"  `--- Sandbox\n"
+	output := renderOutput(func(w io.Writer) {
+		displayTree(w, testStyleOU, Options{ShowAccounts: true, Connectors: ASCIIConnectors})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+}
+
+func TestDisplayTreeColour(t *testing.T) {
+	expectedOutput := "" +
+		"└─┬─ Root\n" +
+		"  ├─── \x1b[1;33mManagement\x1b[0m (111111111111) ACTIVE\n" +
+		"  ├─┬─ Workloads\n" +
+		"  │ ├─── Prod (222222222222) ACTIVE\n" +
+		"  │ ├─── \x1b[31mLegacy\x1b[0m (333333333333) SUSPENDED\n" +
+		"  │ └─── \x1b[2mEmpty\x1b[0m\n" +
+		"  └─── \x1b[2mSandbox\x1b[0m\n"
+	output := renderOutput(func(w io.Writer) {
+		displayTree(w, testStyleOU, Options{ShowAccounts: true, Colour: true, managementAccountId: "111111111111"})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+}
+
+func TestColourSupported(t *testing.T) {
+	// A pipe isn't a terminal.
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+	t.Setenv("NO_COLOR", "")
+	require.False(t, ColourSupported(w))
+
+	// A closed file can't be checked.
+	w.Close()
+	require.False(t, ColourSupported(w))
+
+	// NO_COLOR turns colours off even on a character device, which is how
+	// terminals are detected.
+	tty, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer tty.Close()
+	require.True(t, ColourSupported(tty))
+	t.Setenv("NO_COLOR", "1")
+	require.False(t, ColourSupported(tty))
+}
@@ -2,18 +2,25 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
 )
 
 // tree is a struct that holds the information needed to display the tree in the
 // CLI. It is used with the setupTreeRecursive and printTreeRecursive functions.
+// Each node is either an OU, an account leaf if referencedAccount is set or a
+// leaf summarising the OUs hidden by the depth limit if summary is set.
 type tree struct {
-	referencedNode *generation.OU
-	prefix         string
-	spaces         []string
-	children       []tree
+	referencedNode    *generation.OU
+	referencedAccount *generation.Account
+	summary           string
+	prefix            string
+	spaces            []string
+	children          []tree
 }
 
 // vars for displaying the tree
@@ -51,9 +58,15 @@ var (
 //
 // For example, if a node is the last child of its parent, it should have the
 // endFork prefix. If it is not the last child, it should have the fork prefix.
-func setupTreeRecursive(parentTree tree) tree {
+//
+// If the ShowAccounts option is set, the accounts in each OU are added as
+// leaves before its child OUs. The depth of a Node is the number of spaces
+// before it, and the child OUs of a Node at the MaxDepth option are replaced
+// with a single leaf summarising them.
+func setupTreeRecursive(parentTree tree, opts Options) tree {
 	// Base case
-	if len(parentTree.referencedNode.Children) == 0 {
+	ou := parentTree.referencedNode
+	if len(ou.Children) == 0 && (!opts.ShowAccounts || len(ou.Accounts) == 0) {
 		return tree{}
 	}
 
@@ -70,25 +83,64 @@ func setupTreeRecursive(parentTree tree) tree {
 		parentTree.prefix = endForkChild
 	}
 
-	// For each child, add it to the children slice
-	for _, child := range parentTree.referencedNode.Children {
-		parentTree.children = append(
-			parentTree.children,
-			tree{
-				referencedNode: child,
-				prefix:         fork,
-				spaces:         append(parentTree.spaces, spaceToAppend),
-				children:       []tree{},
-			},
-		)
+	// Copy the spaces so that the children don't share the parent's slice,
+	// which their own children would otherwise overwrite when appending
+	spaces := make([]string, len(parentTree.spaces), len(parentTree.spaces)+1)
+	copy(spaces, parentTree.spaces)
+	spaces = append(spaces, spaceToAppend)
+
+	// For each account, add it to the children slice if requested
+	if opts.ShowAccounts {
+		for i := range ou.Accounts {
+			parentTree.children = append(
+				parentTree.children,
+				tree{
+					referencedAccount: &ou.Accounts[i],
+					prefix:            fork,
+					spaces:            spaces,
+					children:          []tree{},
+				},
+			)
+		}
+	}
+
+	// For each child, add it to the children slice, or summarise the children
+	// in a single leaf if the depth limit has been reached
+	if opts.MaxDepth > 0 && len(parentTree.spaces) >= opts.MaxDepth {
+		if summary := ou.CollapsedSummary(); summary != "" {
+			parentTree.children = append(
+				parentTree.children,
+				tree{
+					summary:  summary,
+					prefix:   fork,
+					spaces:   spaces,
+					children: []tree{},
+				},
+			)
+		}
+	} else {
+		for _, child := range ou.Children {
+			parentTree.children = append(
+				parentTree.children,
+				tree{
+					referencedNode: child,
+					prefix:         fork,
+					spaces:         spaces,
+					children:       []tree{},
+				},
+			)
+		}
 	}
 
 	// Fix the prefix for the last child
 	parentTree.children[len(parentTree.children)-1].prefix = endFork
 
-	// For each child, recursively call this function
+	// For each child OU, recursively call this function
 	for i := range parentTree.children {
-		childValue := setupTreeRecursive(parentTree.children[i])
+		if parentTree.children[i].referencedNode == nil {
+			continue
+		}
+		childValue := setupTreeRecursive(parentTree.children[i], opts)
 		if childValue.referencedNode != nil {
 			parentTree.children[i] = childValue
 		}
@@ -97,30 +149,75 @@ func setupTreeRecursive(parentTree tree) tree {
 	return parentTree
 }
 
-// printTreeRecursive is a recursive function that prints the tree of OUs in the
-// CLI using the information from the tree struct. The detailed bool is used to
-// determine whether to print the number of accounts in each OU.
-func printTreeRecursive(display tree, detailed bool) {
+// printTreeRecursive is a recursive function that writes the tree of OUs to the
+// writer using the information from the tree struct. The Detailed option is used
+// to determine whether to print the number of accounts in each OU, and the
+// Totals option whether to add the totals for the OUs below it. Any
+// policies attached to an OU and the values of the chosen tags are listed
+// after its name. Accounts are printed with their ID and status.
+//
+// The lines are drawn with the chosen connectors, and the names of the
+// management account, suspended accounts and empty OUs are coloured if
+// colours are enabled.
+func printTreeRecursive(w io.Writer, display tree, opts Options) {
+	spaces := ""
+	for _, space := range display.spaces {
+		spaces += opts.Connectors.translate(space)
+	}
+	prefix := opts.Connectors.translate(display.prefix)
+
+	if display.summary != "" {
+		fmt.Fprintf(w, "%s%s%s\n", spaces, prefix, colourise(display.summary, colourEmpty, opts.Colour))
+		return
+	}
+
+	if display.referencedAccount != nil {
+		account := display.referencedAccount
+		colour := accountColour(aws.ToString(account.Id), account.Status, opts.managementAccountId)
+		fmt.Fprintf(w, "%s%s%s (%s) %s%s%s\n",
+			spaces,
+			prefix,
+			colourise(aws.ToString(account.Name), colour, opts.Colour),
+			aws.ToString(account.Id),
+			account.Status,
+			tagInfo(account.Tags, opts.TagKeys),
+			policyInfo(account.Policies),
+		)
+		return
+	}
+
 	info := ""
-	if detailed {
+	if opts.Detailed && opts.Totals {
+		info = fmt.Sprintf(
+			" (%d, %s)",
+			len(display.referencedNode.Accounts),
+			totalInfo(display.referencedNode),
+		)
+	} else if opts.Detailed {
 		info = fmt.Sprintf(
 			" (%d)",
 			len(display.referencedNode.Accounts),
 		)
 	}
-	fmt.Printf("%s%s%s%s\n",
-		strings.Join(display.spaces[:], ""),
-		display.prefix,
-		display.referencedNode.Name,
+	colour := ""
+	if len(display.referencedNode.Accounts) == 0 && len(display.referencedNode.Children) == 0 {
+		colour = colourEmpty
+	}
+	fmt.Fprintf(w, "%s%s%s%s%s%s\n",
+		spaces,
+		prefix,
+		colourise(display.referencedNode.Name, colour, opts.Colour),
+		tagInfo(display.referencedNode.Tags, opts.TagKeys),
 		info,
+		policyInfo(display.referencedNode.Policies),
 	)
 	for _, child := range display.children {
-		printTreeRecursive(child, detailed)
+		printTreeRecursive(w, child, opts)
 	}
 }
 
 /*
-	 displayTree is a function that displays the tree of OUs in the CLI using a
+	 displayTree is a function that writes the tree of OUs to the writer using a
 	 tree structure similar to the one below:
 	 └─┬─ TestOU (2)
 	   ├─┬─ TestOU2 (5)
@@ -128,6 +225,13 @@ func printTreeRecursive(display tree, detailed bool) {
 	   └─┬─ TestOU4 (12)
 		 └─── TestOU5 (2)
 
+	 or with the accounts shown:
+	 └─┬─ TestOU
+	   ├─── Management (111111111111) ACTIVE
+	   └─┬─ TestOU2
+	     ├─── Prod (222222222222) ACTIVE
+	     └─── Legacy (333333333333) SUSPENDED
+
 	 or without the detailed output:
 	 └─┬─ TestOU
 	   ├─┬─ TestOU2
@@ -135,13 +239,87 @@ func printTreeRecursive(display tree, detailed bool) {
 	   └─┬─ TestOU4
 	     └─── TestOU5
 */
-func displayTree(ouTree *generation.OU, detailed bool) {
+func displayTree(w io.Writer, ouTree *generation.OU, opts Options) {
 	parentDisplay := tree{
 		referencedNode: ouTree,
 		prefix:         endFork,
 		spaces:         []string{},
 		children:       []tree{},
 	}
-	display := setupTreeRecursive(parentDisplay)
-	printTreeRecursive(display, detailed)
+	display := setupTreeRecursive(parentDisplay, opts)
+	// A root with nothing below it is displayed on its own
+	if display.referencedNode == nil {
+		display = parentDisplay
+	}
+	printTreeRecursive(w, display, opts)
+}
+
+// totalStatuses are the account statuses that are counted in the totals, in
+// the order they are shown.
+var totalStatuses = []types.AccountStatus{
+	types.AccountStatusActive,
+	types.AccountStatusSuspended,
+	types.AccountStatusPendingClosure,
+}
+
+// totalInfo returns the number of accounts in the OU and every OU below it with
+// the number that have each status in the form "10 total: 8 ACTIVE,
+// 2 SUSPENDED", leaving out the statuses that no account has.
+func totalInfo(ou *generation.OU) string {
+	statuses := ou.AccountsByStatus()
+	counts := make([]string, 0, len(totalStatuses))
+	for _, status := range totalStatuses {
+		if statuses[status] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", statuses[status], status))
+		}
+	}
+	info := fmt.Sprintf("%d total", ou.TotalAccounts())
+	if len(counts) == 0 {
+		return info
+	}
+	return info + ": " + strings.Join(counts, ", ")
+}
+
+// policyLabels are the short names used for each policy type in the tree.
+var policyLabels = map[types.PolicyType]string{
+	types.PolicyTypeServiceControlPolicy:   "SCP",
+	types.PolicyTypeTagPolicy:              "TAG",
+	types.PolicyTypeBackupPolicy:           "BACKUP",
+	types.PolicyTypeAiservicesOptOutPolicy: "AI_OPT_OUT",
+	generation.PolicyTypeChatbotPolicy:     "CHATBOT",
+}
+
+// policyInfo returns the names of the given policies grouped by type in the
+// form " [SCP: FullAWSAccess, DenyRegions] [TAG: CostCentre]", or an empty
+// string if there are none.
+func policyInfo(policies generation.Policies) string {
+	info := ""
+	for _, policyType := range policies.Types() {
+		names := make([]string, len(policies[policyType]))
+		for i, policy := range policies[policyType] {
+			names[i] = policy.Name
+		}
+		label, ok := policyLabels[policyType]
+		if !ok {
+			label = string(policyType)
+		}
+		info += fmt.Sprintf(" [%s: %s]", label, strings.Join(names, ", "))
+	}
+	return info
+}
+
+// tagInfo returns the values of the chosen tags in the form
+// " {owner=platform, env=prod}", skipping any keys that aren't set. It returns
+// an empty string if none of the keys are set.
+func tagInfo(tags map[string]string, keys []string) string {
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if value, ok := tags[key]; ok {
+			values = append(values, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" {%s}", strings.Join(values, ", "))
 }
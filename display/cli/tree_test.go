@@ -25,20 +25,87 @@ func TestPrintTreeRecursive(t *testing.T) {
 
 	// Print the tree with detailed output.
 	expectedOutput := "└─── TestOU (0)\n"
-	output := captureOutput(func() {
-		printTreeRecursive(tree, true)
+	output := renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{Detailed: true})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
 
 	// Print the tree without detailed output.
 	expectedOutput = "└─── TestOU\n"
-	output = captureOutput(func() {
-		printTreeRecursive(tree, false)
+	output = renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
 
 }
 
+func TestPrintTreeRecursivePolicies(t *testing.T) {
+	// Create a tree to test with, the OU has two policies attached.
+	tree := tree{
+		referencedNode: &generation.OU{
+			Name: "TestOU",
+			Policies: generation.Policies{
+				types.PolicyTypeTagPolicy: {
+					{Name: "CostCentre"},
+				},
+				types.PolicyTypeServiceControlPolicy: {
+					{Name: "FullAWSAccess"},
+					{Name: "DenyRegions"},
+				},
+			},
+		},
+		prefix:   endFork,
+		spaces:   []string{},
+		children: []tree{},
+	}
+
+	// Print the tree with detailed output.
+	expectedOutput := "└─── TestOU (0) [SCP: FullAWSAccess, DenyRegions] [TAG: CostCentre]\n"
+	output := renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{Detailed: true})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
+
+	// Print the tree without detailed output.
+	expectedOutput = "└─── TestOU [SCP: FullAWSAccess, DenyRegions] [TAG: CostCentre]\n"
+	output = renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
+}
+
+func TestPrintTreeRecursiveTags(t *testing.T) {
+	// Create a tree to test with, the OU has three tags.
+	tree := tree{
+		referencedNode: &generation.OU{
+			Name: "TestOU",
+			Tags: map[string]string{
+				"owner":       "platform",
+				"env":         "prod",
+				"cost-centre": "1234",
+			},
+		},
+		prefix:   endFork,
+		spaces:   []string{},
+		children: []tree{},
+	}
+
+	// Print the chosen tags in the order they were given, skipping any that
+	// aren't set.
+	expectedOutput := "└─── TestOU {env=prod, owner=platform} (0)\n"
+	output := renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{Detailed: true, TagKeys: []string{"env", "missing", "owner"}})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
+
+	// Print nothing if none of the chosen tags are set.
+	expectedOutput = "└─── TestOU\n"
+	output = renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{TagKeys: []string{"missing"}})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
+}
+
 func TestPrintTreeRecursiveDeep(t *testing.T) {
 	tree := tree{
 		referencedNode: &generation.OU{
@@ -77,8 +144,8 @@ func TestPrintTreeRecursiveDeep(t *testing.T) {
 		"└─┬─ TestOU (0)\n" +
 		"  └─┬─ TestOU2 (0)\n" +
 		"    └─── TestOU3 (0)\n"
-	output := captureOutput(func() {
-		printTreeRecursive(tree, true)
+	output := renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{Detailed: true})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
 
@@ -87,8 +154,8 @@ func TestPrintTreeRecursiveDeep(t *testing.T) {
 		"└─┬─ TestOU\n" +
 		"  └─┬─ TestOU2\n" +
 		"    └─── TestOU3\n"
-	output = captureOutput(func() {
-		printTreeRecursive(tree, false)
+	output = renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
 
@@ -156,8 +223,8 @@ func TestPrintTreeRecursiveWide(t *testing.T) {
 		"  │ └─── TestOU3 (0)\n" +
 		"  └─┬─ TestOU4 (0)\n" +
 		"    └─── TestOU5 (0)\n"
-	output := captureOutput(func() {
-		printTreeRecursive(tree, true)
+	output := renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{Detailed: true})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
 
@@ -168,8 +235,8 @@ func TestPrintTreeRecursiveWide(t *testing.T) {
 		"  │ └─── TestOU3\n" +
 		"  └─┬─ TestOU4\n" +
 		"    └─── TestOU5\n"
-	output = captureOutput(func() {
-		printTreeRecursive(tree, false)
+	output = renderOutput(func(w io.Writer) {
+		printTreeRecursive(w, tree, Options{})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not printed correctly")
 
@@ -187,7 +254,7 @@ func TestSetupTreeRecursiveSimple(t *testing.T) {
 	}
 
 	// Return a simple tree
-	returnedTree := setupTreeRecursive(tree)
+	returnedTree := setupTreeRecursive(tree, Options{})
 
 	// Check that the tree was returned correctly.
 	require.Equal(t, tree.prefix, returnedTree.prefix, "Tree prefix was not set correctly")
@@ -224,7 +291,7 @@ func TestSetupTreeRecursiveComplicated(t *testing.T) {
 		children: []tree{},
 	}
 
-	returnedTree := setupTreeRecursive(tree)
+	returnedTree := setupTreeRecursive(tree, Options{})
 
 	// Require the root to have 2 children.
 	require.Len(t, returnedTree.children, 2, "Tree children was not set correctly")
@@ -281,12 +348,16 @@ func TestDisplayTree(t *testing.T) {
 				},
 			},
 		},
-		Accounts: []types.Account{
+		Accounts: []generation.Account{
 			{
-				Name: aws.String("TestAccount"),
+				Account: types.Account{
+					Name: aws.String("TestAccount"),
+				},
 			},
 			{
-				Name: aws.String("TestAccount2"),
+				Account: types.Account{
+					Name: aws.String("TestAccount2"),
+				},
 			},
 		},
 	}
@@ -298,8 +369,8 @@ func TestDisplayTree(t *testing.T) {
 		"  │ └─── TestOU3 (0)\n" +
 		"  └─┬─ TestOU3 (0)\n" +
 		"    └─── TestOU4 (0)\n"
-	output := captureOutput(func() {
-		displayTree(ou, true)
+	output := renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{Detailed: true})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
 
@@ -310,8 +381,233 @@ func TestDisplayTree(t *testing.T) {
 		"  │ └─── TestOU3\n" +
 		"  └─┬─ TestOU3\n" +
 		"    └─── TestOU4\n"
-	output = captureOutput(func() {
-		displayTree(ou, false)
+	output = renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+}
+
+func TestDisplayTreeEmptyRoot(t *testing.T) {
+	ou := &generation.OU{
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+		},
+	}
+
+	// Display the root on its own if it has no OUs and the accounts aren't
+	// shown.
+	output := renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{Detailed: true})
+	})
+	require.Equal(t, "└─── Root (1)\n", output, "Tree was not displayed correctly")
+
+	// Display its accounts below it if they are shown.
+	expectedOutput := "" +
+		"└─┬─ Root (1)\n" +
+		"  └─── Management (111111111111) ACTIVE\n"
+	output = renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{Detailed: true, ShowAccounts: true})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+}
+
+func TestDisplayTreeAccounts(t *testing.T) {
+	ou := &generation.OU{
+		Name: "Root",
+		Accounts: []generation.Account{
+			{
+				Account: types.Account{
+					Id:     aws.String("111111111111"),
+					Name:   aws.String("Management"),
+					Status: types.AccountStatusActive,
+				},
+			},
+		},
+		Children: []*generation.OU{
+			{
+				Name: "Workloads",
+				Accounts: []generation.Account{
+					{
+						Account: types.Account{
+							Id:     aws.String("222222222222"),
+							Name:   aws.String("Prod"),
+							Status: types.AccountStatusActive,
+						},
+					},
+					{
+						Account: types.Account{
+							Id:     aws.String("333333333333"),
+							Name:   aws.String("Legacy"),
+							Status: types.AccountStatusSuspended,
+						},
+					},
+				},
+				Children: []*generation.OU{
+					{
+						Name: "Sandbox",
+						Accounts: []generation.Account{
+							{
+								Account: types.Account{
+									Id:     aws.String("444444444444"),
+									Name:   aws.String("Dev"),
+									Status: types.AccountStatusActive,
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "Empty",
+			},
+		},
+	}
+
+	// Display the accounts as leaves before the child OUs, continuing the
+	// lines of the OUs that have later siblings.
+	expectedOutput := "" +
+		"└─┬─ Root (1)\n" +
+		"  ├─── Management (111111111111) ACTIVE\n" +
+		"  ├─┬─ Workloads (2)\n" +
+		"  │ ├─── Prod (222222222222) ACTIVE\n" +
+		"  │ ├─── Legacy (333333333333) SUSPENDED\n" +
+		"  │ └─┬─ Sandbox (1)\n" +
+		"  │   └─── Dev (444444444444) ACTIVE\n" +
+		"  └─── Empty (0)\n"
+	output := renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{Detailed: true, ShowAccounts: true})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+
+	// Display only the OUs if the accounts aren't requested.
+	expectedOutput = "" +
+		"└─┬─ Root\n" +
+		"  ├─┬─ Workloads\n" +
+		"  │ └─── Sandbox\n" +
+		"  └─── Empty\n"
+	output = renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+}
+
+func TestDisplayTreeTotals(t *testing.T) {
+	ou := &generation.OU{
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Status: types.AccountStatusActive}},
+		},
+		Children: []*generation.OU{
+			{
+				Name: "Workloads",
+				Accounts: []generation.Account{
+					{Account: types.Account{Status: types.AccountStatusActive}},
+					{Account: types.Account{Status: types.AccountStatusSuspended}},
+				},
+				Children: []*generation.OU{
+					{
+						Name: "Closing",
+						Accounts: []generation.Account{
+							{Account: types.Account{Status: types.AccountStatusPendingClosure}},
+						},
+					},
+				},
+			},
+			{
+				Name: "Empty",
+			},
+		},
+	}
+
+	// Display the totals for each subtree after the direct account count.
+	expectedOutput := "" +
+		"└─┬─ Root (1, 4 total: 2 ACTIVE, 1 SUSPENDED, 1 PENDING_CLOSURE)\n" +
+		"  ├─┬─ Workloads (2, 3 total: 1 ACTIVE, 1 SUSPENDED, 1 PENDING_CLOSURE)\n" +
+		"  │ └─── Closing (1, 1 total: 1 PENDING_CLOSURE)\n" +
+		"  └─── Empty (0, 0 total)\n"
+	output := renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{Detailed: true, Totals: true})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+
+	// The totals are only shown in detailed mode.
+	output = renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{Totals: true})
+	})
+	require.NotContains(t, output, "total", "Totals were displayed without detailed mode")
+}
+
+func TestDisplayTreeMaxDepth(t *testing.T) {
+	ou := &generation.OU{
+		Name: "Root",
+		Children: []*generation.OU{
+			{
+				Name: "Workloads",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Prod"), Status: types.AccountStatusActive}},
+				},
+				Children: []*generation.OU{
+					{
+						Name: "Team",
+						Accounts: []generation.Account{
+							{Account: types.Account{Status: types.AccountStatusActive}},
+						},
+						Children: []*generation.OU{
+							{
+								Name: "Sandbox",
+								Accounts: []generation.Account{
+									{Account: types.Account{Status: types.AccountStatusActive}},
+									{Account: types.Account{Status: types.AccountStatusActive}},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "Empty",
+			},
+		},
+	}
+
+	// Summarise the OUs below the first level, still showing the accounts of
+	// the OUs that are shown.
+	expectedOutput := "" +
+		"└─┬─ Root\n" +
+		"  ├─┬─ Workloads\n" +
+		"  │ ├─── Prod (222222222222) ACTIVE\n" +
+		"  │ └─── … 2 more OUs, 3 accounts\n" +
+		"  └─── Empty\n"
+	output := renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{ShowAccounts: true, MaxDepth: 1})
+	})
+	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
+
+	// Show every level if the limit is deeper than the tree.
+	output = renderOutput(func(w io.Writer) {
+		displayTree(w, ou, Options{MaxDepth: 3})
+	})
+	require.NotContains(t, output, "more OUs", "Tree was collapsed below the depth limit")
+
+	// Show the accounts of a leaf OU at the limit without a summary.
+	leaf := &generation.OU{
+		Name: "Root",
+		Children: []*generation.OU{
+			{
+				Name: "Workloads",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Prod"), Status: types.AccountStatusActive}},
+				},
+			},
+		},
+	}
+	expectedOutput = "" +
+		"└─┬─ Root\n" +
+		"  └─┬─ Workloads\n" +
+		"    └─── Prod (222222222222) ACTIVE\n"
+	output = renderOutput(func(w io.Writer) {
+		displayTree(w, leaf, Options{ShowAccounts: true, MaxDepth: 1})
 	})
 	require.Equal(t, expectedOutput, output, "Tree was not displayed correctly")
 }
@@ -338,3 +634,11 @@ func captureOutput(f func()) string {
 	// Return the output.
 	return buf.String()
 }
+
+// renderOutput is a helper function to capture what a function writes to the
+// writer it is given. This is used to test the output of the display functions.
+func renderOutput(f func(w io.Writer)) string {
+	var buf bytes.Buffer
+	f(&buf)
+	return buf.String()
+}
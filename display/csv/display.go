@@ -0,0 +1,165 @@
+// # Display/CSV
+//
+// This package contains the code for the CSV display of the AWS accounts. It
+// flattens the tree generated in the generation package into a single table
+// with a row for each account, so that the accounts can be opened in a
+// spreadsheet.
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Column is a column of the table, named by its header.
+type Column string
+
+const (
+	ColumnId              Column = "id"
+	ColumnName            Column = "name"
+	ColumnEmail           Column = "email"
+	ColumnStatus          Column = "status"
+	ColumnJoinedMethod    Column = "joined_method"
+	ColumnJoinedTimestamp Column = "joined_timestamp"
+	ColumnOUPath          Column = "ou_path"
+	ColumnOUId            Column = "ou_id"
+)
+
+// AllColumns is every column, in the order they are output by default.
+var AllColumns = []Column{
+	ColumnId,
+	ColumnName,
+	ColumnEmail,
+	ColumnStatus,
+	ColumnJoinedMethod,
+	ColumnJoinedTimestamp,
+	ColumnOUPath,
+	ColumnOUId,
+}
+
+// Options holds the settings that control the table.
+type Options struct {
+	// Columns is the columns to output, in order. Every column is output if
+	// it is empty.
+	Columns []Column
+	// Delimiter separates the fields of each row, it defaults to a comma.
+	Delimiter rune
+}
+
+// ParseColumns returns the columns named in the comma separated list, or every
+// column if the list is empty.
+func ParseColumns(list string) ([]Column, error) {
+	columns := make([]Column, 0)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !isKnownColumn(Column(name)) {
+			return nil, fmt.Errorf("unknown column %s", name)
+		}
+		columns = append(columns, Column(name))
+	}
+	if len(columns) == 0 {
+		return AllColumns, nil
+	}
+	return columns, nil
+}
+
+// isKnownColumn returns whether the column is one of AllColumns.
+func isKnownColumn(column Column) bool {
+	for _, known := range AllColumns {
+		if column == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Create is a function that takes in the organization and creates a table
+// with a header row followed by a row for each account, in the order they
+// appear in the tree.
+func Create(org *generation.Organization, opts Options) ([]byte, error) {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = AllColumns
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if opts.Delimiter != 0 {
+		writer.Comma = opts.Delimiter
+	}
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = string(column)
+	}
+	err := writer.Write(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var walk func(ou *generation.OU, path string) error
+	walk = func(ou *generation.OU, path string) error {
+		for _, account := range ou.Accounts {
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				row[i] = field(account, ou, path, column)
+			}
+			err := writer.Write(row)
+			if err != nil {
+				return err
+			}
+		}
+		for _, child := range ou.Children {
+			err := walk(child, path+"/"+child.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = walk(org.Root, org.Root.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// field returns the value of the column for the account in the given OU.
+func field(account generation.Account, ou *generation.OU, path string, column Column) string {
+	switch column {
+	case ColumnId:
+		return aws.ToString(account.Id)
+	case ColumnName:
+		return aws.ToString(account.Name)
+	case ColumnEmail:
+		return aws.ToString(account.Email)
+	case ColumnStatus:
+		return string(account.Status)
+	case ColumnJoinedMethod:
+		return string(account.JoinedMethod)
+	case ColumnJoinedTimestamp:
+		if account.JoinedTimestamp == nil {
+			return ""
+		}
+		return account.JoinedTimestamp.UTC().Format(time.RFC3339)
+	case ColumnOUPath:
+		return path
+	case ColumnOUId:
+		return ou.Id
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package csv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testOrganization is the organization displayed in the tests below.
+var testOrganization = &generation.Organization{
+	Root: &generation.OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{
+				Id:              aws.String("111111111111"),
+				Name:            aws.String("Management"),
+				Email:           aws.String("management@example.com"),
+				Status:          types.AccountStatusActive,
+				JoinedMethod:    types.AccountJoinedMethodInvited,
+				JoinedTimestamp: aws.Time(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+			}},
+		},
+		Children: []*generation.OU{
+			{
+				Id:   "ou-1",
+				Name: "Work, loads",
+				Accounts: []generation.Account{
+					{Account: types.Account{
+						Id:     aws.String("222222222222"),
+						Name:   aws.String(`Prod "main"`),
+						Status: types.AccountStatusSuspended,
+					}},
+				},
+			},
+		},
+	},
+}
+
+// TestCreate tests that there is a row for each account with every column.
+func TestCreate(t *testing.T) {
+	table, err := Create(testOrganization, Options{})
+	require.NoError(t, err)
+	expected := "" +
+		"id,name,email,status,joined_method,joined_timestamp,ou_path,ou_id\n" +
+		"111111111111,Management,management@example.com,ACTIVE,INVITED,2020-01-02T03:04:05Z,Root,r-1234\n" +
+		"222222222222,\"Prod \"\"main\"\"\",,SUSPENDED,,,\"Root/Work, loads\",ou-1\n"
+	require.Equal(t, expected, string(table))
+}
+
+// TestCreateOptions tests that the columns and delimiter can be chosen.
+func TestCreateOptions(t *testing.T) {
+	table, err := Create(testOrganization, Options{
+		Columns:   []Column{ColumnOUPath, ColumnId},
+		Delimiter: '\t',
+	})
+	require.NoError(t, err)
+	expected := "" +
+		"ou_path\tid\n" +
+		"Root\t111111111111\n" +
+		"Root/Work, loads\t222222222222\n"
+	require.Equal(t, expected, string(table))
+
+	_, err = Create(testOrganization, Options{Delimiter: '"'})
+	require.Error(t, err)
+}
+
+// TestParseColumns tests that the columns are parsed from a comma separated
+// list.
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns("")
+	require.NoError(t, err)
+	require.Equal(t, AllColumns, columns)
+
+	columns, err = ParseColumns(" ID, ou_path ,")
+	require.NoError(t, err)
+	require.Equal(t, []Column{ColumnId, ColumnOUPath}, columns)
+
+	_, err = ParseColumns("id,cost")
+	require.Error(t, err)
+}
@@ -0,0 +1,133 @@
+// # Display/DOT
+//
+// This package contains the code for the Graphviz DOT display of the AWS
+// accounts and OUs. It uses the organization generated in the generation
+// package to create a DOT document that can be rendered with Graphviz, for
+// example with `dot -Tsvg org.dot -o org.svg`.
+package dot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// Options holds the settings that control what is shown in the DOT document.
+type Options struct {
+	// IncludeAccounts adds each account as a leaf below the OU it is in.
+	IncludeAccounts bool
+	// Clusters draws each OU as a box around its accounts and child OUs,
+	// rather than as a node with an edge to each of them.
+	Clusters bool
+	// MaxDepth is the number of levels of OUs below the root to show, the OUs
+	// below that are summarised in a single node. Every level is shown if it
+	// is 0.
+	MaxDepth int
+}
+
+// The attributes used to style each kind of node in the document.
+const (
+	graphStyle             = `rankdir=LR; fontname="Helvetica";`
+	ouStyle                = `shape=box, style="rounded,filled", fillcolor="#dbe8fb", fontname="Helvetica"`
+	accountStyle           = `shape=note, style=filled, fillcolor="#ffffff", fontname="Helvetica"`
+	managementAccountStyle = `shape=note, style=filled, fillcolor="#fff1b8", penwidth=2, fontname="Helvetica"`
+	suspendedAccountStyle  = `shape=note, style="filled,dashed", fillcolor="#eeeeee", fontcolor="#888888", fontname="Helvetica"`
+	collapsedStyle         = `shape=plaintext, style="", fontcolor="#888888", fontname="Helvetica"`
+)
+
+// Create is a function that takes in the organization and creates a DOT
+// representation of its tree of OUs, and optionally its accounts.
+func Create(org *generation.Organization, opts Options) []byte {
+	var doc strings.Builder
+	doc.WriteString("digraph organization {\n")
+	fmt.Fprintf(&doc, "  %s\n", graphStyle)
+	fmt.Fprintf(&doc, "  node [%s];\n", ouStyle)
+	if opts.Clusters {
+		writeCluster(&doc, org, org.Root, opts, "  ", 0)
+	} else {
+		writeNodes(&doc, org, org.Root, opts, 0)
+	}
+	doc.WriteString("}\n")
+	return []byte(doc.String())
+}
+
+// writeNodes writes the OU as a node with an edge to each of its accounts and
+// child OUs, then writes each child OU in the same way. The child OUs of an OU
+// at the maximum depth are replaced with a node summarising them.
+func writeNodes(doc *strings.Builder, org *generation.Organization, ou *generation.OU, opts Options, depth int) {
+	fmt.Fprintf(doc, "  %s [label=%s];\n", quote(ou.Id), quote(ou.Name))
+	if opts.IncludeAccounts {
+		for _, account := range ou.Accounts {
+			writeAccount(doc, org, account, "  ")
+			fmt.Fprintf(doc, "  %s -> %s;\n", quote(ou.Id), quote(aws.ToString(account.Id)))
+		}
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		if summary := ou.CollapsedSummary(); summary != "" {
+			fmt.Fprintf(doc, "  %s [label=%s, %s];\n", quote(ou.Id+"_more"), quote(summary), collapsedStyle)
+			fmt.Fprintf(doc, "  %s -> %s;\n", quote(ou.Id), quote(ou.Id+"_more"))
+		}
+		return
+	}
+	for _, child := range ou.Children {
+		fmt.Fprintf(doc, "  %s -> %s;\n", quote(ou.Id), quote(child.Id))
+	}
+	for _, child := range ou.Children {
+		writeNodes(doc, org, child, opts, depth+1)
+	}
+}
+
+// writeCluster writes the OU as a cluster holding its accounts and a cluster
+// for each of its child OUs, or a node summarising them if the OU is at the
+// maximum depth.
+func writeCluster(doc *strings.Builder, org *generation.Organization, ou *generation.OU, opts Options, indent string, depth int) {
+	fmt.Fprintf(doc, "%ssubgraph %s {\n", indent, quote("cluster_"+ou.Id))
+	fmt.Fprintf(doc, "%s  label=%s; style=rounded; color=\"#5b8def\";\n", indent, quote(ou.Name))
+
+	// Graphviz doesn't draw empty clusters, so give them a hidden node.
+	empty := len(ou.Children) == 0 && (!opts.IncludeAccounts || len(ou.Accounts) == 0)
+	if empty {
+		fmt.Fprintf(doc, "%s  %s [shape=point, style=invis];\n", indent, quote(ou.Id))
+	}
+	if opts.IncludeAccounts {
+		for _, account := range ou.Accounts {
+			writeAccount(doc, org, account, indent+"  ")
+		}
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		if summary := ou.CollapsedSummary(); summary != "" {
+			fmt.Fprintf(doc, "%s  %s [label=%s, %s];\n", indent, quote(ou.Id+"_more"), quote(summary), collapsedStyle)
+		}
+	} else {
+		for _, child := range ou.Children {
+			writeCluster(doc, org, child, opts, indent+"  ", depth+1)
+		}
+	}
+	fmt.Fprintf(doc, "%s}\n", indent)
+}
+
+// writeAccount writes the account as a node labelled with its name and ID,
+// styled differently if it is the management account or suspended.
+func writeAccount(doc *strings.Builder, org *generation.Organization, account generation.Account, indent string) {
+	id := aws.ToString(account.Id)
+	style := accountStyle
+	if account.Status == types.AccountStatusSuspended {
+		style = suspendedAccountStyle
+	} else if id != "" && id == org.ManagementAccountId {
+		style = managementAccountStyle
+	}
+	label := aws.ToString(account.Name) + "\n" + id
+	fmt.Fprintf(doc, "%s%s [label=%s, %s];\n", indent, quote(id), quote(label), style)
+}
+
+// quote returns the string as a quoted DOT ID, escaping any quotes and
+// backslashes and turning new lines into DOT line breaks.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,144 @@
+package dot
+
+import (
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testOrganization is the organization displayed in the tests below.
+var testOrganization = &generation.Organization{
+	ManagementAccountId: "111111111111",
+	Root: &generation.OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+		},
+		Children: []*generation.OU{
+			{
+				Id:   "ou-1",
+				Name: `Work "loads"`,
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Closed"), Status: types.AccountStatusSuspended}},
+				},
+			},
+			{Id: "ou-2", Name: "Empty"},
+		},
+	},
+}
+
+// TestCreate tests the DOT document with each OU as a node.
+func TestCreate(t *testing.T) {
+	expected := "" +
+		"digraph organization {\n" +
+		"  " + graphStyle + "\n" +
+		"  node [" + ouStyle + "];\n" +
+		"  \"r-1234\" [label=\"Root\"];\n" +
+		"  \"r-1234\" -> \"ou-1\";\n" +
+		"  \"r-1234\" -> \"ou-2\";\n" +
+		"  \"ou-1\" [label=\"Work \\\"loads\\\"\"];\n" +
+		"  \"ou-2\" [label=\"Empty\"];\n" +
+		"}\n"
+	require.Equal(t, expected, string(Create(testOrganization, Options{})))
+
+	// The accounts are styled by their status and whether they are the
+	// management account.
+	expected = "" +
+		"digraph organization {\n" +
+		"  " + graphStyle + "\n" +
+		"  node [" + ouStyle + "];\n" +
+		"  \"r-1234\" [label=\"Root\"];\n" +
+		"  \"111111111111\" [label=\"Management\\n111111111111\", " + managementAccountStyle + "];\n" +
+		"  \"r-1234\" -> \"111111111111\";\n" +
+		"  \"r-1234\" -> \"ou-1\";\n" +
+		"  \"r-1234\" -> \"ou-2\";\n" +
+		"  \"ou-1\" [label=\"Work \\\"loads\\\"\"];\n" +
+		"  \"222222222222\" [label=\"Closed\\n222222222222\", " + suspendedAccountStyle + "];\n" +
+		"  \"ou-1\" -> \"222222222222\";\n" +
+		"  \"ou-2\" [label=\"Empty\"];\n" +
+		"}\n"
+	require.Equal(t, expected, string(Create(testOrganization, Options{IncludeAccounts: true})))
+}
+
+// TestCreateClusters tests the DOT document with each OU as a cluster.
+func TestCreateClusters(t *testing.T) {
+	expected := "" +
+		"digraph organization {\n" +
+		"  " + graphStyle + "\n" +
+		"  node [" + ouStyle + "];\n" +
+		"  subgraph \"cluster_r-1234\" {\n" +
+		"    label=\"Root\"; style=rounded; color=\"#5b8def\";\n" +
+		"    \"111111111111\" [label=\"Management\\n111111111111\", " + managementAccountStyle + "];\n" +
+		"    subgraph \"cluster_ou-1\" {\n" +
+		"      label=\"Work \\\"loads\\\"\"; style=rounded; color=\"#5b8def\";\n" +
+		"      \"222222222222\" [label=\"Closed\\n222222222222\", " + suspendedAccountStyle + "];\n" +
+		"    }\n" +
+		"    subgraph \"cluster_ou-2\" {\n" +
+		"      label=\"Empty\"; style=rounded; color=\"#5b8def\";\n" +
+		"      \"ou-2\" [shape=point, style=invis];\n" +
+		"    }\n" +
+		"  }\n" +
+		"}\n"
+	require.Equal(t, expected, string(Create(testOrganization, Options{IncludeAccounts: true, Clusters: true})))
+}
+
+// TestCreateMaxDepth tests that the OUs below the maximum depth are
+// summarised in a single node.
+func TestCreateMaxDepth(t *testing.T) {
+	org := &generation.Organization{
+		Root: &generation.OU{
+			Id:   "r-1234",
+			Name: "Root",
+			Children: []*generation.OU{
+				{
+					Id:   "ou-1",
+					Name: "Workloads",
+					Children: []*generation.OU{
+						{
+							Id:       "ou-2",
+							Name:     "Team",
+							Accounts: []generation.Account{{Account: types.Account{Id: aws.String("222222222222")}}},
+							Children: []*generation.OU{{Id: "ou-3", Name: "Sandbox"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := "" +
+		"digraph organization {\n" +
+		"  " + graphStyle + "\n" +
+		"  node [" + ouStyle + "];\n" +
+		"  \"r-1234\" [label=\"Root\"];\n" +
+		"  \"r-1234\" -> \"ou-1\";\n" +
+		"  \"ou-1\" [label=\"Workloads\"];\n" +
+		"  \"ou-1_more\" [label=\"… 2 more OUs, 1 accounts\", " + collapsedStyle + "];\n" +
+		"  \"ou-1\" -> \"ou-1_more\";\n" +
+		"}\n"
+	require.Equal(t, expected, string(Create(org, Options{MaxDepth: 1})))
+
+	expected = "" +
+		"digraph organization {\n" +
+		"  " + graphStyle + "\n" +
+		"  node [" + ouStyle + "];\n" +
+		"  subgraph \"cluster_r-1234\" {\n" +
+		"    label=\"Root\"; style=rounded; color=\"#5b8def\";\n" +
+		"    subgraph \"cluster_ou-1\" {\n" +
+		"      label=\"Workloads\"; style=rounded; color=\"#5b8def\";\n" +
+		"      \"ou-1_more\" [label=\"… 2 more OUs, 1 accounts\", " + collapsedStyle + "];\n" +
+		"    }\n" +
+		"  }\n" +
+		"}\n"
+	require.Equal(t, expected, string(Create(org, Options{Clusters: true, MaxDepth: 1})))
+}
+
+// TestQuote tests that DOT IDs are escaped.
+func TestQuote(t *testing.T) {
+	require.Equal(t, `"plain"`, quote("plain"))
+	require.Equal(t, `"a \"b\" \\ c\nd"`, quote("a \"b\" \\ c\nd"))
+}
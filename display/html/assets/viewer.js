@@ -0,0 +1,149 @@
+(function () {
+  "use strict";
+
+  var org = JSON.parse(document.getElementById("org-data").textContent);
+  var tree = document.getElementById("tree");
+  var details = document.getElementById("details");
+  var search = document.getElementById("search");
+  var filters = document.querySelectorAll(".status-filter");
+  var selected = null;
+
+  // el creates an element with the given class and text.
+  function el(tag, className, text) {
+    var node = document.createElement(tag);
+    if (className) node.className = className;
+    if (text !== undefined && text !== null) node.textContent = text;
+    return node;
+  }
+
+  // countAccounts returns the number of accounts in the OU and every OU
+  // below it.
+  function countAccounts(ou) {
+    var total = (ou.accounts || []).length;
+    (ou.children || []).forEach(function (child) { total += countAccounts(child); });
+    return total;
+  }
+
+  // renderOU creates the list item for the OU, its accounts and its
+  // children.
+  function renderOU(ou) {
+    var item = el("li", "ou");
+    var label = el("span", "label", ou.name);
+    label.appendChild(el("span", "count", " (" + countAccounts(ou) + ")"));
+    label.addEventListener("click", function () { item.classList.toggle("collapsed"); });
+    item.appendChild(label);
+
+    var list = el("ul");
+    (ou.accounts || []).forEach(function (account) { list.appendChild(renderAccount(account, ou)); });
+    (ou.children || []).forEach(function (child) { list.appendChild(renderOU(child)); });
+    item.appendChild(list);
+    return item;
+  }
+
+  // renderAccount creates the list item for the account, which shows its
+  // details when clicked.
+  function renderAccount(account, ou) {
+    var item = el("li", "account");
+    item.dataset.search = [account.Name, account.Id, account.Email].join(" ").toLowerCase();
+    item.dataset.status = account.Status || "";
+    item.appendChild(el("span", "name", account.Name));
+    item.appendChild(el("span", "id", " " + account.Id));
+    item.appendChild(el("span", "status status-" + account.Status, account.Status));
+    if (org.managementAccountId && account.Id === org.managementAccountId) {
+      item.appendChild(el("span", "management", "management"));
+    }
+    item.addEventListener("click", function () {
+      if (selected) selected.classList.remove("selected");
+      selected = item;
+      item.classList.add("selected");
+      showDetails(account, ou);
+    });
+    return item;
+  }
+
+  // addRows adds a term and description to the list for each entry.
+  function addRows(list, rows) {
+    rows.forEach(function (row) {
+      if (row[1] === undefined || row[1] === null || row[1] === "") return;
+      list.appendChild(el("dt", "", row[0]));
+      list.appendChild(el("dd", "", row[1]));
+    });
+  }
+
+  // showDetails fills the details pane with everything known about the
+  // account.
+  function showDetails(account, ou) {
+    details.textContent = "";
+    details.appendChild(el("h2", "", account.Name));
+    var list = el("dl");
+    addRows(list, [
+      ["ID", account.Id],
+      ["ARN", account.Arn],
+      ["Email", account.Email],
+      ["Status", account.Status],
+      ["Joined", account.JoinedMethod ? account.JoinedMethod + " " + (account.JoinedTimestamp || "") : account.JoinedTimestamp],
+      ["OU", ou.name + " (" + ou.id + ")"]
+    ]);
+    details.appendChild(list);
+
+    if (account.tags && Object.keys(account.tags).length > 0) {
+      details.appendChild(el("h3", "", "Tags"));
+      var tags = el("dl");
+      addRows(tags, Object.keys(account.tags).sort().map(function (key) { return [key, account.tags[key]]; }));
+      details.appendChild(tags);
+    }
+    if (account.policies && Object.keys(account.policies).length > 0) {
+      details.appendChild(el("h3", "", "Policies"));
+      var policies = el("dl");
+      addRows(policies, Object.keys(account.policies).sort().map(function (type) {
+        return [type, account.policies[type].map(function (p) { return p.name; }).join(", ")];
+      }));
+      details.appendChild(policies);
+    }
+    if (account.delegatedServices && account.delegatedServices.length > 0) {
+      details.appendChild(el("h3", "", "Delegated administrator for"));
+      var services = el("ul");
+      account.delegatedServices.forEach(function (service) { services.appendChild(el("li", "", service)); });
+      details.appendChild(services);
+    }
+  }
+
+  // applyFilters hides the accounts that don't match the search or the
+  // chosen statuses, and the OUs left with nothing to show while searching.
+  function applyFilters() {
+    var query = search.value.trim().toLowerCase();
+    var statuses = {};
+    filters.forEach(function (filter) { statuses[filter.value] = filter.checked; });
+
+    tree.querySelectorAll(".account").forEach(function (item) {
+      var matches = item.dataset.search.indexOf(query) !== -1 && statuses[item.dataset.status] !== false;
+      item.classList.toggle("hidden", !matches);
+    });
+    var ous = Array.prototype.slice.call(tree.querySelectorAll(".ou")).reverse();
+    ous.forEach(function (item) {
+      var visible = item.querySelector(".account:not(.hidden)") !== null;
+      item.classList.toggle("hidden", query !== "" && !visible);
+      if (query !== "" && visible) item.classList.remove("collapsed");
+    });
+  }
+
+  var root = el("ul");
+  root.appendChild(renderOU(org.root));
+  tree.appendChild(root);
+
+  var summary = document.getElementById("summary");
+  var parts = [];
+  if (org.id) parts.push("Organization " + org.id);
+  if (org.managementAccountId) parts.push("management account " + org.managementAccountId);
+  parts.push(countAccounts(org.root) + " accounts");
+  summary.textContent = parts.join(", ");
+
+  search.addEventListener("input", applyFilters);
+  filters.forEach(function (filter) { filter.addEventListener("change", applyFilters); });
+  document.getElementById("expand-all").addEventListener("click", function () {
+    tree.querySelectorAll(".ou").forEach(function (item) { item.classList.remove("collapsed"); });
+  });
+  document.getElementById("collapse-all").addEventListener("click", function () {
+    tree.querySelectorAll(".ou").forEach(function (item, i) { if (i > 0) item.classList.add("collapsed"); });
+  });
+})();
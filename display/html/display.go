@@ -0,0 +1,65 @@
+// # Display/HTML
+//
+// This package contains the code for the HTML display of the AWS accounts and
+// OUs. It uses the organization generated in the generation package to create
+// a single, self contained HTML file with the organization embedded in it and
+// a small viewer to explore it offline, with collapsible OUs, a search of the
+// accounts, filters by status and the details of each account.
+package html
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"os"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+)
+
+// The viewer is bundled into the binary so the HTML file needs nothing else.
+var (
+	//go:embed assets/viewer.html
+	viewerHTML string
+	//go:embed assets/viewer.css
+	viewerCSS string
+	//go:embed assets/viewer.js
+	viewerJS string
+)
+
+// viewer is the template of the HTML file.
+var viewer = template.Must(template.New("viewer").Parse(viewerHTML))
+
+// page is the data the viewer template is executed with.
+type page struct {
+	Title        string
+	CSS          template.CSS
+	JS           template.JS
+	Organization *generation.Organization
+}
+
+// Create is a function that takes in the organization and creates the HTML
+// file that embeds it along with the viewer.
+func Create(org *generation.Organization) ([]byte, error) {
+	title := "AWS Organization"
+	if org.Id != "" {
+		title += " " + org.Id
+	}
+
+	var buf bytes.Buffer
+	err := viewer.Execute(&buf, page{
+		Title:        title,
+		CSS:          template.CSS(viewerCSS),
+		JS:           template.JS(viewerJS),
+		Organization: org,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// OutputToFile is a function that takes in the HTML file and writes it to the
+// given file.
+func OutputToFile(html []byte, filename string) error {
+	return os.WriteFile(filename, html, 0o644)
+}
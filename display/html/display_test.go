@@ -0,0 +1,57 @@
+package html
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/json"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testOrganization is the organization displayed in the tests below, with an
+// account name that would end the embedded script if it wasn't escaped.
+var testOrganization = &generation.Organization{
+	Id: "o-1234",
+	Root: &generation.OU{
+		Id:       "r-1234",
+		Name:     "Root",
+		Children: []*generation.OU{},
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("</script><b>bold</b>"), Status: types.AccountStatusActive}},
+		},
+	},
+}
+
+// organizationData matches the organization embedded in the HTML file.
+var organizationData = regexp.MustCompile(`<script id="org-data" type="application/json">(.*)</script>`)
+
+// TestCreate tests that the HTML file embeds the viewer and the organization,
+// which can be read back unchanged.
+func TestCreate(t *testing.T) {
+	page, err := Create(testOrganization)
+	require.NoError(t, err)
+	require.Contains(t, string(page), "<title>AWS Organization o-1234</title>")
+	require.Contains(t, string(page), viewerCSS)
+	require.Contains(t, string(page), viewerJS)
+	require.NotContains(t, string(page), "</script><b>")
+
+	match := organizationData.FindSubmatch(page)
+	require.NotNil(t, match, "organization was not embedded")
+	org, err := json.Load(match[1])
+	require.NoError(t, err)
+	require.Equal(t, testOrganization, org)
+}
+
+// TestOutputToFile tests that the HTML file is written to the given file.
+func TestOutputToFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "org.html")
+	require.NoError(t, OutputToFile([]byte("<html></html>"), filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "<html></html>", string(data))
+}
@@ -1,21 +1,23 @@
 // # Display/JSON
 //
 // This package contains the code for the JSON display of the AWS accounts and
-// OUs. It uses the tree structure generated in the generation package to
-// create a JSON representation of the accounts and OUs.
+// OUs. It uses the organization generated in the generation package to create
+// a JSON representation of the organization, its accounts and OUs.
 package json
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
 )
 
-// Create is a function that takes in the tree structure and creates a JSON
-// representation of it.
-func Create(tree *generation.OU) ([]byte, error) {
+// Create is a function that takes in the organization and creates a JSON
+// representation of it, with the tree structure under the "root" field.
+func Create(org *generation.Organization) ([]byte, error) {
 	// Use the existing structure to create a JSON representation of the tree.
-	return tree.ToJSON()
+	return org.ToJSON()
 }
 
 // OutputToFile is a function that takes in the json representaiton of the tree and
@@ -35,3 +37,47 @@ func OutputToFile(json []byte, filename string) error {
 	}
 	return nil
 }
+
+// Load is a function that takes in a JSON representation of the organization,
+// as created by Create, and turns it back into the organization. Files written
+// before the organization document existed only hold the tree of OUs, these
+// are loaded into an organization with just the root set.
+func Load(data []byte) (*generation.Organization, error) {
+	// Work out which format the JSON is in from its top level fields.
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the snapshot: %w", err)
+	}
+
+	if _, ok := fields["root"]; ok {
+		org := &generation.Organization{}
+		err = json.Unmarshal(data, org)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the snapshot: %w", err)
+		}
+		if org.Root == nil {
+			return nil, fmt.Errorf("failed to parse the snapshot: the root is empty")
+		}
+		return org, nil
+	}
+	if _, ok := fields["id"]; !ok {
+		return nil, fmt.Errorf("failed to parse the snapshot: no root or OU found")
+	}
+	root := &generation.OU{}
+	err = json.Unmarshal(data, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the snapshot: %w", err)
+	}
+	return &generation.Organization{Root: root}, nil
+}
+
+// ReadFromFile is a function that reads the JSON representation of the
+// organization from the given file, as written by OutputToFile.
+func ReadFromFile(filename string) (*generation.Organization, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
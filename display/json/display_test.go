@@ -0,0 +1,91 @@
+package json
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadFromFile tests that an organization written by OutputToFile is read
+// back by ReadFromFile unchanged.
+func TestReadFromFile(t *testing.T) {
+	org := &generation.Organization{
+		Id:         "o-1234",
+		FeatureSet: types.OrganizationFeatureSetAll,
+		Root: &generation.OU{
+			Id:   "r-1234",
+			Name: "Root",
+			Children: []*generation.OU{
+				{
+					Id:       "ou-1",
+					Name:     "Workloads",
+					Children: []*generation.OU{},
+					Accounts: []generation.Account{
+						{
+							Account: types.Account{
+								Id:     aws.String("222222222222"),
+								Name:   aws.String("Prod"),
+								Status: types.AccountStatusActive,
+							},
+							Tags: map[string]string{"env": "prod"},
+						},
+					},
+				},
+			},
+			Accounts: []generation.Account{},
+			Policies: generation.Policies{
+				types.PolicyTypeServiceControlPolicy: {{Id: "p-full", Name: "FullAWSAccess"}},
+			},
+		},
+	}
+
+	jsonTree, err := Create(org)
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	err = OutputToFile(jsonTree, filename)
+	require.NoError(t, err)
+
+	loaded, err := ReadFromFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, org, loaded)
+
+	_, err = ReadFromFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+// TestLoadTree tests that a file holding only the tree of OUs is loaded into
+// an organization with just the root set.
+func TestLoadTree(t *testing.T) {
+	root := &generation.OU{
+		Id:       "r-1234",
+		Name:     "Root",
+		Children: []*generation.OU{},
+		Accounts: []generation.Account{},
+	}
+	jsonTree, err := root.ToJSON()
+	require.NoError(t, err)
+
+	org, err := Load(jsonTree)
+	require.NoError(t, err)
+	require.Equal(t, &generation.Organization{Root: root}, org)
+}
+
+// TestLoadInvalid tests that Load returns an error for JSON that isn't a
+// snapshot.
+func TestLoadInvalid(t *testing.T) {
+	for _, data := range []string{`not json`, `[]`, `{}`, `{"root": null}`, `{"id": 1}`} {
+		_, err := Load([]byte(data))
+		require.Error(t, err, data)
+	}
+
+	// The file must exist and be readable.
+	filename := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{}`), 0o600))
+	_, err := ReadFromFile(filename)
+	require.Error(t, err)
+}
@@ -0,0 +1,139 @@
+// # Display/Markdown
+//
+// This package contains the code for the Markdown display of the AWS accounts
+// and OUs. It uses the organization generated in the generation package to
+// create a report with a summary of counts, a nested list of the OUs and a
+// table of the accounts in each OU, that can be committed into a docs
+// repository.
+package markdown
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// Options holds the settings that control what is shown in the report.
+type Options struct {
+	// MaxDepth is the number of levels of OUs below the root to list and show
+	// the accounts of, the OUs below that are summarised in a single list
+	// item. Every level is shown if it is 0. The summary always counts every
+	// OU and account.
+	MaxDepth int
+}
+
+// Create is a function that takes in the organization and creates a Markdown
+// report of it. The report holds nothing that changes between runs other than
+// the organization itself, so it only differs when the organization does.
+func Create(org *generation.Organization, opts Options) []byte {
+	var doc strings.Builder
+	doc.WriteString("# AWS Organization\n\n")
+	if org.Id != "" {
+		fmt.Fprintf(&doc, "- Organization: `%s`\n", org.Id)
+		fmt.Fprintf(&doc, "- Management account: `%s` (%s)\n", org.ManagementAccountId, escape(org.ManagementAccountEmail))
+		fmt.Fprintf(&doc, "- Feature set: %s\n\n", org.FeatureSet)
+	}
+
+	writeSummary(&doc, org.Root)
+
+	doc.WriteString("## Organizational units\n\n")
+	writeOUList(&doc, org.Root, opts, 0)
+	doc.WriteString("\n")
+
+	doc.WriteString("## Accounts\n")
+	writeAccountTables(&doc, org.Root, org.Root.Name, opts, 0)
+	return []byte(doc.String())
+}
+
+// accountStatuses is the statuses that accounts are counted by in the summary,
+// in the order they are shown.
+var accountStatuses = []types.AccountStatus{
+	types.AccountStatusActive,
+	types.AccountStatusSuspended,
+	types.AccountStatusPendingClosure,
+}
+
+// writeSummary writes a table of the number of OUs and accounts in the tree,
+// with the accounts broken down by their status.
+func writeSummary(doc *strings.Builder, root *generation.OU) {
+	statuses := root.AccountsByStatus()
+	doc.WriteString("## Summary\n\n")
+	doc.WriteString("| | Count |\n")
+	doc.WriteString("| --- | ---: |\n")
+	fmt.Fprintf(doc, "| Organizational units | %d |\n", root.TotalOUs())
+	fmt.Fprintf(doc, "| Accounts | %d |\n", root.TotalAccounts())
+	for _, status := range accountStatuses {
+		fmt.Fprintf(doc, "| %s accounts | %d |\n", status, statuses[status])
+	}
+	doc.WriteString("\n")
+}
+
+// writeOUList writes a nested list item for the OU and each of its children,
+// indented by their depth below the root. The children of an OU at the maximum
+// depth are summarised in a single item.
+func writeOUList(doc *strings.Builder, ou *generation.OU, opts Options, depth int) {
+	fmt.Fprintf(doc, "%s- %s (`%s`) - %d account(s)\n", strings.Repeat("  ", depth), escape(ou.Name), ou.Id, len(ou.Accounts))
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		if summary := ou.CollapsedSummary(); summary != "" {
+			fmt.Fprintf(doc, "%s- %s\n", strings.Repeat("  ", depth+1), summary)
+		}
+		return
+	}
+	for _, child := range ou.Children {
+		writeOUList(doc, child, opts, depth+1)
+	}
+}
+
+// writeAccountTables writes a heading with the path of each OU that has
+// accounts followed by a table of those accounts, in the order they appear in
+// the tree and down to the maximum depth.
+func writeAccountTables(doc *strings.Builder, ou *generation.OU, path string, opts Options, depth int) {
+	if len(ou.Accounts) > 0 {
+		fmt.Fprintf(doc, "\n### %s\n\n", escape(path))
+		doc.WriteString("| Name | ID | Email | Status | Joined |\n")
+		doc.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, account := range ou.Accounts {
+			joined := ""
+			if account.JoinedTimestamp != nil {
+				joined = account.JoinedTimestamp.UTC().Format("2006-01-02")
+			}
+			fmt.Fprintf(doc, "| %s | `%s` | %s | %s | %s |\n",
+				escape(aws.ToString(account.Name)),
+				aws.ToString(account.Id),
+				escape(aws.ToString(account.Email)),
+				account.Status,
+				joined,
+			)
+		}
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		return
+	}
+	for _, child := range ou.Children {
+		writeAccountTables(doc, child, path+"/"+child.Name, opts, depth+1)
+	}
+}
+
+// escape escapes the characters in the text that would otherwise be read as
+// Markdown, or break a table cell.
+func escape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// markdownEscaper backslash escapes the characters that have a meaning in
+// Markdown.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"#", `\#`,
+)
@@ -0,0 +1,107 @@
+package markdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreate tests that the report holds the summary, the list of OUs and a
+// table for each OU with accounts.
+func TestCreate(t *testing.T) {
+	org := &generation.Organization{
+		Id:                     "o-1234",
+		ManagementAccountId:    "111111111111",
+		ManagementAccountEmail: "management@example.com",
+		FeatureSet:             types.OrganizationFeatureSetAll,
+		Root: &generation.OU{
+			Id:   "r-1234",
+			Name: "Root",
+			Accounts: []generation.Account{
+				{Account: types.Account{
+					Id:              aws.String("111111111111"),
+					Name:            aws.String("Management"),
+					Email:           aws.String("management@example.com"),
+					Status:          types.AccountStatusActive,
+					JoinedTimestamp: aws.Time(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+				}},
+			},
+			Children: []*generation.OU{
+				{Id: "ou-1", Name: "Empty"},
+				{
+					Id:   "ou-2",
+					Name: "Prod | EU",
+					Accounts: []generation.Account{
+						{Account: types.Account{
+							Id:     aws.String("222222222222"),
+							Name:   aws.String("app_one"),
+							Status: types.AccountStatusSuspended,
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := "" +
+		"# AWS Organization\n" +
+		"\n" +
+		"- Organization: `o-1234`\n" +
+		"- Management account: `111111111111` (management@example.com)\n" +
+		"- Feature set: ALL\n" +
+		"\n" +
+		"## Summary\n" +
+		"\n" +
+		"| | Count |\n" +
+		"| --- | ---: |\n" +
+		"| Organizational units | 2 |\n" +
+		"| Accounts | 2 |\n" +
+		"| ACTIVE accounts | 1 |\n" +
+		"| SUSPENDED accounts | 1 |\n" +
+		"| PENDING_CLOSURE accounts | 0 |\n" +
+		"\n" +
+		"## Organizational units\n" +
+		"\n" +
+		"- Root (`r-1234`) - 1 account(s)\n" +
+		"  - Empty (`ou-1`) - 0 account(s)\n" +
+		"  - Prod \\| EU (`ou-2`) - 1 account(s)\n" +
+		"\n" +
+		"## Accounts\n" +
+		"\n" +
+		"### Root\n" +
+		"\n" +
+		"| Name | ID | Email | Status | Joined |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| Management | `111111111111` | management@example.com | ACTIVE | 2020-01-02 |\n" +
+		"\n" +
+		"### Root/Prod \\| EU\n" +
+		"\n" +
+		"| Name | ID | Email | Status | Joined |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| app\\_one | `222222222222` |  | SUSPENDED |  |\n"
+	require.Equal(t, expected, string(Create(org, Options{})))
+
+	// The organization details are left out if they aren't known.
+	org.Id = ""
+	require.NotContains(t, string(Create(org, Options{})), "- Organization:")
+
+	// The OUs below the maximum depth are summarised, and their accounts left
+	// out of the tables but not the summary.
+	org.Root.Children[1].Children = []*generation.OU{
+		{
+			Id:   "ou-3",
+			Name: "Team",
+			Accounts: []generation.Account{
+				{Account: types.Account{Id: aws.String("333333333333"), Name: aws.String("Team")}},
+			},
+		},
+	}
+	report := string(Create(org, Options{MaxDepth: 1}))
+	require.Contains(t, report, "  - Prod \\| EU (`ou-2`) - 1 account(s)\n    - … 1 more OUs, 1 accounts\n")
+	require.Contains(t, report, "| Accounts | 3 |\n")
+	require.NotContains(t, report, "333333333333")
+}
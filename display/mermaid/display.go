@@ -0,0 +1,164 @@
+// # Display/Mermaid
+//
+// This package contains the code for the Mermaid display of the AWS accounts
+// and OUs. It uses the organization generated in the generation package to
+// create a Mermaid flowchart or mindmap that can be embedded in Markdown
+// documents on sites that render Mermaid, such as GitHub.
+package mermaid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// Diagram is the kind of Mermaid diagram to create.
+type Diagram string
+
+const (
+	Flowchart Diagram = "flowchart"
+	Mindmap   Diagram = "mindmap"
+)
+
+// Options holds the settings that control what is shown in the diagram.
+type Options struct {
+	// Diagram is the kind of diagram to create, it defaults to a flowchart.
+	Diagram Diagram
+	// IncludeAccounts adds each account as a leaf below the OU it is in.
+	IncludeAccounts bool
+	// IncludeAccountIds adds the ID of each account to its name.
+	IncludeAccountIds bool
+	// MaxDepth is the number of levels of OUs below the root to show, the OUs
+	// below that are summarised in a single node. Every level is shown if it
+	// is 0.
+	MaxDepth int
+}
+
+// ParseDiagram returns the kind of diagram with the given name.
+func ParseDiagram(name string) (Diagram, error) {
+	switch Diagram(name) {
+	case Flowchart, Mindmap:
+		return Diagram(name), nil
+	}
+	return "", fmt.Errorf("unknown Mermaid diagram %s, expected flowchart or mindmap", name)
+}
+
+// Create is a function that takes in the organization and creates a Mermaid
+// diagram of its tree of OUs, and optionally its accounts.
+func Create(org *generation.Organization, opts Options) []byte {
+	var doc strings.Builder
+	if opts.Diagram == Mindmap {
+		doc.WriteString("mindmap\n")
+		writeMindmap(&doc, org, org.Root, opts, 0)
+	} else {
+		doc.WriteString("flowchart LR\n")
+		writeFlowchart(&doc, org, org.Root, opts, 0)
+		doc.WriteString("  classDef management stroke-width:3px,stroke:#b58900\n")
+		doc.WriteString("  classDef suspended stroke-dasharray:5 5,color:#888888\n")
+	}
+	return []byte(doc.String())
+}
+
+// writeFlowchart writes the OU as a node with an edge to each of its accounts
+// and child OUs, then writes each child OU in the same way.
+func writeFlowchart(doc *strings.Builder, org *generation.Organization, ou *generation.OU, opts Options, depth int) {
+	if depth == 0 {
+		fmt.Fprintf(doc, "  %s[\"%s\"]\n", nodeId(ou.Id), escape(ou.Name))
+	}
+	if opts.IncludeAccounts {
+		for _, account := range ou.Accounts {
+			id := nodeId(aws.ToString(account.Id))
+			fmt.Fprintf(doc, "  %s --> %s([\"%s\"])\n", nodeId(ou.Id), id, escape(accountLabel(account, opts)))
+			if class := accountClass(org, account); class != "" {
+				fmt.Fprintf(doc, "  class %s %s\n", id, class)
+			}
+		}
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		if summary := ou.CollapsedSummary(); summary != "" {
+			fmt.Fprintf(doc, "  %s --> %s[\"%s\"]\n", nodeId(ou.Id), nodeId(ou.Id+"_more"), escape(summary))
+		}
+		return
+	}
+	for _, child := range ou.Children {
+		fmt.Fprintf(doc, "  %s --> %s[\"%s\"]\n", nodeId(ou.Id), nodeId(child.Id), escape(child.Name))
+		writeFlowchart(doc, org, child, opts, depth+1)
+	}
+}
+
+// writeMindmap writes the OU as a node indented below its parent, followed by
+// its accounts and child OUs indented below it.
+func writeMindmap(doc *strings.Builder, org *generation.Organization, ou *generation.OU, opts Options, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	if depth == 0 {
+		fmt.Fprintf(doc, "%s%s((\"%s\"))\n", indent, nodeId(ou.Id), escape(ou.Name))
+	} else {
+		fmt.Fprintf(doc, "%s%s[\"%s\"]\n", indent, nodeId(ou.Id), escape(ou.Name))
+	}
+	if opts.IncludeAccounts {
+		for _, account := range ou.Accounts {
+			fmt.Fprintf(doc, "%s  %s(\"%s\")\n", indent, nodeId(aws.ToString(account.Id)), escape(accountLabel(account, opts)))
+		}
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		if summary := ou.CollapsedSummary(); summary != "" {
+			fmt.Fprintf(doc, "%s  %s[\"%s\"]\n", indent, nodeId(ou.Id+"_more"), escape(summary))
+		}
+		return
+	}
+	for _, child := range ou.Children {
+		writeMindmap(doc, org, child, opts, depth+1)
+	}
+}
+
+// accountLabel returns the text shown for the account, its name and
+// optionally its ID.
+func accountLabel(account generation.Account, opts Options) string {
+	label := aws.ToString(account.Name)
+	if opts.IncludeAccountIds {
+		label += fmt.Sprintf(" (%s)", aws.ToString(account.Id))
+	}
+	return label
+}
+
+// accountClass returns the class used to style the account in a flowchart, or
+// an empty string if it isn't styled.
+func accountClass(org *generation.Organization, account generation.Account) string {
+	if account.Status == types.AccountStatusSuspended {
+		return "suspended"
+	}
+	if id := aws.ToString(account.Id); id != "" && id == org.ManagementAccountId {
+		return "management"
+	}
+	return ""
+}
+
+// invalidIdChars matches the characters that can't be used in a Mermaid node
+// ID.
+var invalidIdChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// nodeId returns the ID of an OU or account as a Mermaid node ID, prefixed so
+// that account IDs don't start with a digit.
+func nodeId(id string) string {
+	return "n_" + invalidIdChars.ReplaceAllString(id, "_")
+}
+
+// escaper replaces the characters that break Mermaid labels with their entity
+// codes.
+var escaper = strings.NewReplacer(
+	`#`, `#35;`,
+	`"`, `#quot;`,
+	`<`, `#lt;`,
+	`>`, `#gt;`,
+	"\n", " ",
+)
+
+// escape returns the name with any characters that break Mermaid labels
+// replaced, so it can be used inside a quoted label.
+func escape(name string) string {
+	return escaper.Replace(name)
+}
@@ -0,0 +1,94 @@
+package mermaid
+
+import (
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testOrganization is the organization displayed in the tests below.
+var testOrganization = &generation.Organization{
+	ManagementAccountId: "111111111111",
+	Root: &generation.OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+		},
+		Children: []*generation.OU{
+			{
+				Id:   "ou-1234-1",
+				Name: `Work "loads" <#1>`,
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Closed"), Status: types.AccountStatusSuspended}},
+				},
+				Children: []*generation.OU{
+					{Id: "ou-1234-2", Name: "Deep"},
+				},
+			},
+		},
+	},
+}
+
+// TestCreateFlowchart tests the flowchart of the OUs, with and without the
+// accounts.
+func TestCreateFlowchart(t *testing.T) {
+	expected := "" +
+		"flowchart LR\n" +
+		"  n_r_1234[\"Root\"]\n" +
+		"  n_r_1234 --> n_ou_1234_1[\"Work #quot;loads#quot; #lt;#35;1#gt;\"]\n" +
+		"  n_ou_1234_1 --> n_ou_1234_2[\"Deep\"]\n" +
+		"  classDef management stroke-width:3px,stroke:#b58900\n" +
+		"  classDef suspended stroke-dasharray:5 5,color:#888888\n"
+	require.Equal(t, expected, string(Create(testOrganization, Options{})))
+
+	expected = "" +
+		"flowchart LR\n" +
+		"  n_r_1234[\"Root\"]\n" +
+		"  n_r_1234 --> n_111111111111([\"Management (111111111111)\"])\n" +
+		"  class n_111111111111 management\n" +
+		"  n_r_1234 --> n_ou_1234_1[\"Work #quot;loads#quot; #lt;#35;1#gt;\"]\n" +
+		"  n_ou_1234_1 --> n_222222222222([\"Closed (222222222222)\"])\n" +
+		"  class n_222222222222 suspended\n" +
+		"  n_ou_1234_1 --> n_ou_1234_1_more[\"… 1 more OUs, 0 accounts\"]\n" +
+		"  classDef management stroke-width:3px,stroke:#b58900\n" +
+		"  classDef suspended stroke-dasharray:5 5,color:#888888\n"
+	require.Equal(t, expected, string(Create(testOrganization, Options{
+		IncludeAccounts:   true,
+		IncludeAccountIds: true,
+		MaxDepth:          1,
+	})))
+}
+
+// TestCreateMindmap tests the mindmap of the OUs and accounts.
+func TestCreateMindmap(t *testing.T) {
+	expected := "" +
+		"mindmap\n" +
+		"  n_r_1234((\"Root\"))\n" +
+		"    n_111111111111(\"Management\")\n" +
+		"    n_ou_1234_1[\"Work #quot;loads#quot; #lt;#35;1#gt;\"]\n" +
+		"      n_222222222222(\"Closed\")\n" +
+		"      n_ou_1234_2[\"Deep\"]\n"
+	require.Equal(t, expected, string(Create(testOrganization, Options{Diagram: Mindmap, IncludeAccounts: true})))
+
+	// OUs below the maximum depth are summarised.
+	expected = "" +
+		"mindmap\n" +
+		"  n_r_1234((\"Root\"))\n" +
+		"    n_ou_1234_1[\"Work #quot;loads#quot; #lt;#35;1#gt;\"]\n" +
+		"      n_ou_1234_1_more[\"… 1 more OUs, 0 accounts\"]\n"
+	require.Equal(t, expected, string(Create(testOrganization, Options{Diagram: Mindmap, MaxDepth: 1})))
+}
+
+// TestParseDiagram tests that only the known diagrams are accepted.
+func TestParseDiagram(t *testing.T) {
+	diagram, err := ParseDiagram("mindmap")
+	require.NoError(t, err)
+	require.Equal(t, Mindmap, diagram)
+
+	_, err = ParseDiagram("gantt")
+	require.Error(t, err)
+}
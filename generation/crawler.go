@@ -0,0 +1,245 @@
+package generation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// organizationsAPI is the set of Organizations API calls made when generating
+// the structure, it is satisfied by the organizations.Client.
+type organizationsAPI interface {
+	DescribeOrganization
+	ListRoots
+	ListOrganizationalUnitsForParent
+	ListAccountsForParent
+	ListPoliciesForTarget
+	DescribePolicy
+	DescribeEffectivePolicy
+	ListTagsForResource
+	ListDelegatedAdministrators
+	ListDelegatedServicesForAccount
+	ListAWSServiceAccessForOrganization
+}
+
+// --- crawler -----------------------------------------------------------------
+// crawler walks the AWS Organizations structure, requesting the child OUs and
+// accounts of every OU concurrently. The number of API calls in flight at any
+// one time is bounded by the size of the limiter channel.
+type crawler struct {
+	api         organizationsAPI
+	retry       RetryPolicy
+	policyTypes []types.PolicyType
+	tags        bool
+	limiter     chan struct{}
+	cache       policyCache
+}
+
+// newCrawler creates a crawler that allows at most opts.Parallelism API calls
+// to be in flight at once, falling back to DefaultParallelism if it is not
+// positive.
+func newCrawler(api organizationsAPI, opts Options) *crawler {
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+	return &crawler{
+		api:         api,
+		retry:       opts.Retry,
+		policyTypes: opts.PolicyTypes,
+		tags:        opts.IncludeTags,
+		limiter:     make(chan struct{}, parallelism),
+	}
+}
+
+// limit runs fn once a slot in the limiter is free, returning early if the
+// context is cancelled while waiting.
+func (c *crawler) limit(ctx context.Context, fn func() error) error {
+	select {
+	case c.limiter <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-c.limiter }()
+	return fn()
+}
+
+// fillTree fills the parent OU with its child OUs, accounts, policies and tags
+// and then recursively does the same for each child. Children are stored in the
+// order the API returned them so the final tree is the same regardless of the
+// order the goroutines finish in.
+func (c *crawler) fillTree(ctx context.Context, parent *OU) error {
+	var ous []*OU
+	var accounts []types.Account
+
+	// Get the OUs, accounts, policies and tags for the parent OU at the same
+	// time.
+	err := parallel(ctx,
+		func(ctx context.Context) error {
+			return c.limit(ctx, func() (err error) {
+				ous, err = getOUsForParent(ctx, c.api, c.retry, parent.Id)
+				return err
+			})
+		},
+		func(ctx context.Context) error {
+			return c.limit(ctx, func() (err error) {
+				accounts, err = getAccountsFromOU(ctx, c.api, c.retry, parent.Id, parent.Name)
+				return err
+			})
+		},
+		func(ctx context.Context) (err error) {
+			parent.Policies, err = c.getPolicies(ctx, parent.Id)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			parent.Tags, err = c.getTags(ctx, parent.Id)
+			return err
+		},
+	)
+	if err != nil {
+		return err
+	}
+	parent.addChildren(ous)
+	parent.Accounts = make([]Account, len(accounts))
+
+	// Get the policies and tags for each of the accounts and recursively fill
+	// the tree for each of the children.
+	fns := make([]func(context.Context) error, 0, len(accounts)+len(ous))
+	for i := range accounts {
+		account := &parent.Accounts[i]
+		account.Account = accounts[i]
+		fns = append(fns, func(ctx context.Context) error {
+			return c.fillAccount(ctx, account)
+		})
+	}
+	for i := range ous {
+		child := ous[i]
+		fns = append(fns, func(ctx context.Context) error {
+			return c.fillTree(ctx, child)
+		})
+	}
+	return parallel(ctx, fns...)
+}
+
+// fillAccount fills the account with its policies and tags.
+func (c *crawler) fillAccount(ctx context.Context, account *Account) error {
+	return parallel(ctx,
+		func(ctx context.Context) (err error) {
+			account.Policies, err = c.getPolicies(ctx, *account.Id)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			account.Tags, err = c.getTags(ctx, *account.Id)
+			return err
+		},
+	)
+}
+
+// getTags gets the tags on the given root, OU or account. It returns nil if the
+// crawler hasn't been asked to include tags.
+func (c *crawler) getTags(ctx context.Context, resourceId string) (tags map[string]string, err error) {
+	if !c.tags {
+		return nil, nil
+	}
+	err = c.limit(ctx, func() (err error) {
+		tags, err = getTagsForResource(ctx, c.api, c.retry, resourceId)
+		return err
+	})
+	return tags, err
+}
+
+// getPolicies gets the policies of each of the crawler's policy types that are
+// attached directly to the target, describing each policy to get its content.
+// It returns nil if the crawler hasn't been asked for any policy types.
+func (c *crawler) getPolicies(ctx context.Context, targetId string) (Policies, error) {
+	if len(c.policyTypes) == 0 {
+		return nil, nil
+	}
+
+	// Get the policies of each type at the same time.
+	results := make([][]Policy, len(c.policyTypes))
+	fns := make([]func(context.Context) error, len(c.policyTypes))
+	for i := range c.policyTypes {
+		i := i
+		fns[i] = func(ctx context.Context) (err error) {
+			results[i], err = c.getPoliciesOfType(ctx, targetId, c.policyTypes[i])
+			return err
+		}
+	}
+	err := parallel(ctx, fns...)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := Policies{}
+	for i, policyType := range c.policyTypes {
+		if len(results[i]) > 0 {
+			policies[policyType] = results[i]
+		}
+	}
+	return policies, nil
+}
+
+// getPoliciesOfType gets the policies of a single type that are attached
+// directly to the target, describing each policy to get its content.
+func (c *crawler) getPoliciesOfType(ctx context.Context, targetId string, policyType types.PolicyType) ([]Policy, error) {
+	var summaries []types.PolicySummary
+	err := c.limit(ctx, func() (err error) {
+		summaries, err = getPoliciesForTarget(ctx, c.api, c.retry, targetId, policyType)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]Policy, len(summaries))
+	for i, summary := range summaries {
+		content, err := c.cache.get(*summary.Id, func() (content string, err error) {
+			err = c.limit(ctx, func() (err error) {
+				content, err = getPolicyContent(ctx, c.api, c.retry, *summary.Id)
+				return err
+			})
+			return content, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		policies[i] = Policy{
+			Id:          *summary.Id,
+			Name:        aws.ToString(summary.Name),
+			Type:        summary.Type,
+			Description: aws.ToString(summary.Description),
+			AwsManaged:  summary.AwsManaged,
+			Content:     content,
+		}
+	}
+	return policies, nil
+}
+
+// parallel runs each of the given functions in its own goroutine and waits for
+// them all to finish. The context passed to the functions is cancelled as soon
+// as any of them fails, and the first error is returned.
+func parallel(ctx context.Context, fns ...func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, fn := range fns {
+		wg.Add(1)
+		go func(fn func(context.Context) error) {
+			defer wg.Done()
+			if err := fn(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(fn)
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,234 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testStructure is the structure of OUs returned by the mocks below, keyed by
+// the parent ID.
+var testStructure = map[string][]string{
+	"r-1234":   {"ou-1", "ou-2", "ou-3"},
+	"ou-1":     {"ou-1-1", "ou-1-2"},
+	"ou-2":     {},
+	"ou-3":     {"ou-3-1"},
+	"ou-1-1":   {},
+	"ou-1-2":   {},
+	"ou-3-1":   {"ou-3-1-1"},
+	"ou-3-1-1": {},
+}
+
+// organizationsMock combines the mocks of each of the API calls made when
+// generating the structure so that it can be used as an organizationsAPI.
+type organizationsMock struct {
+	DescribeOrganizationMock
+	ListRootsMock
+	ListOrganizationalUnitsForParentMock
+	ListAccountsForParentMock
+	ListPoliciesForTargetMock
+	DescribePolicyMock
+	DescribeEffectivePolicyMock
+	ListTagsForResourceMock
+	ListDelegatedAdministratorsMock
+	ListDelegatedServicesForAccountMock
+	ListAWSServiceAccessForOrganizationMock
+}
+
+// newTestOrganizationsMock creates an organizationsMock that returns the
+// testStructure with one account in every OU.
+func newTestOrganizationsMock(inFlight, maxInFlight *int32) *organizationsMock {
+	return &organizationsMock{
+		ListOrganizationalUnitsForParentMock: *newTestOUMock(inFlight, maxInFlight),
+		ListAccountsForParentMock:            *newTestAccountMock(),
+	}
+}
+
+// newTestOUMock creates a ListOrganizationalUnitsForParentMock that returns the
+// testStructure, sleeping for a short random-ish time before each response so
+// that the goroutines finish out of order.
+func newTestOUMock(inFlight, maxInFlight *int32) *ListOrganizationalUnitsForParentMock {
+	return &ListOrganizationalUnitsForParentMock{
+		ListOrganizationalUnitsForParentFunc: func(
+			ctx context.Context,
+			params *organizations.ListOrganizationalUnitsForParentInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListOrganizationalUnitsForParentOutput,
+			error,
+		) {
+			// Track how many calls are running at the same time
+			current := atomic.AddInt32(inFlight, 1)
+			defer atomic.AddInt32(inFlight, -1)
+			for {
+				peak := atomic.LoadInt32(maxInFlight)
+				if current <= peak || atomic.CompareAndSwapInt32(maxInFlight, peak, current) {
+					break
+				}
+			}
+
+			children, ok := testStructure[*params.ParentId]
+			if !ok {
+				return nil, fmt.Errorf("unexpected parent ID %s", *params.ParentId)
+			}
+			time.Sleep(time.Duration(5-len(*params.ParentId)%5) * time.Millisecond)
+
+			output := &organizations.ListOrganizationalUnitsForParentOutput{}
+			for _, id := range children {
+				output.OrganizationalUnits = append(output.OrganizationalUnits, types.OrganizationalUnit{
+					Id:   aws.String(id),
+					Name: aws.String("Name " + id),
+				})
+			}
+			return output, nil
+		},
+	}
+}
+
+// newTestAccountMock creates a ListAccountsForParentMock that returns a single
+// account for every OU, with an ID based on the OU ID.
+func newTestAccountMock() *ListAccountsForParentMock {
+	return &ListAccountsForParentMock{
+		ListAccountsForParentFunc: func(
+			ctx context.Context,
+			params *organizations.ListAccountsForParentInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListAccountsForParentOutput,
+			error,
+		) {
+			return &organizations.ListAccountsForParentOutput{
+				Accounts: []types.Account{
+					{
+						Id:   aws.String("acc-" + *params.ParentId),
+						Name: aws.String("Account " + *params.ParentId),
+					},
+				},
+			}, nil
+		},
+	}
+}
+
+// TestCrawlerFillTree tests the fillTree method fills in the OUs and accounts
+// of the whole tree.
+func TestCrawlerFillTree(t *testing.T) {
+	var inFlight, maxInFlight int32
+	c := newCrawler(newTestOrganizationsMock(&inFlight, &maxInFlight), Options{Retry: testRetryPolicy, Parallelism: 4})
+
+	// Fill the tree from the root.
+	tree := &OU{Id: "r-1234", Name: "Root"}
+	ctx := context.Background()
+	err := c.fillTree(ctx, tree)
+	require.NoError(t, err, "fillTree returned an error")
+
+	// Check that the children were added correctly.
+	require.Len(t, tree.Children, 3, "OU children was not set correctly")
+	require.Equal(t, "ou-1", tree.Children[0].Id, "OU children was not set correctly")
+	require.Equal(t, "Name ou-1", tree.Children[0].Name, "OU children was not set correctly")
+	require.Len(t, tree.Children[0].Children, 2, "OU children was not set correctly")
+	require.Len(t, tree.Children[1].Children, 0, "OU children was not set correctly")
+	require.Equal(t, "ou-3-1-1", tree.Children[2].Children[0].Children[0].Id, "OU children was not set correctly")
+
+	// Check that the accounts were added correctly.
+	require.Len(t, tree.Accounts, 1, "OU accounts was not set correctly")
+	require.Equal(t, "acc-r-1234", *tree.Accounts[0].Id, "OU accounts was not set correctly")
+	require.Equal(t, "acc-ou-1-2", *tree.Children[0].Children[1].Accounts[0].Id, "OU accounts was not set correctly")
+}
+
+// TestCrawlerFillTreeDeterministic tests that the order of the children in the
+// tree is always the order returned by the API, however the goroutines finish.
+func TestCrawlerFillTreeDeterministic(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		var inFlight, maxInFlight int32
+		c := newCrawler(newTestOrganizationsMock(&inFlight, &maxInFlight), Options{Retry: testRetryPolicy, Parallelism: 8})
+		tree := &OU{Id: "r-1234", Name: "Root"}
+		err := c.fillTree(context.Background(), tree)
+		require.NoError(t, err, "fillTree returned an error")
+
+		ids := []string{}
+		var walk func(o *OU)
+		walk = func(o *OU) {
+			ids = append(ids, o.Id)
+			for _, child := range o.Children {
+				walk(child)
+			}
+		}
+		walk(tree)
+		require.Equal(t, []string{"r-1234", "ou-1", "ou-1-1", "ou-1-2", "ou-2", "ou-3", "ou-3-1", "ou-3-1-1"}, ids, "OU children were not in a deterministic order")
+	}
+}
+
+// TestCrawlerFillTreeParallelism tests that the crawler never has more API
+// calls in flight than the parallelism limit.
+func TestCrawlerFillTreeParallelism(t *testing.T) {
+	var inFlight, maxInFlight int32
+	c := newCrawler(newTestOrganizationsMock(&inFlight, &maxInFlight), Options{Retry: testRetryPolicy, Parallelism: 1})
+	tree := &OU{Id: "r-1234", Name: "Root"}
+	err := c.fillTree(context.Background(), tree)
+	require.NoError(t, err, "fillTree returned an error")
+	require.Equal(t, int32(1), maxInFlight, "crawler exceeded the parallelism limit")
+
+	// A parallelism of 0 falls back to the default.
+	c = newCrawler(newTestOrganizationsMock(&inFlight, &maxInFlight), Options{Retry: testRetryPolicy, Parallelism: 0})
+	require.Equal(t, DefaultParallelism, cap(c.limiter), "crawler did not use the default parallelism")
+}
+
+// TestCrawlerFillTreeError tests that an error in one branch of the tree is
+// returned and that the other branches are cancelled.
+func TestCrawlerFillTreeError(t *testing.T) {
+	var mu sync.Mutex
+	cancelled := false
+	ouMock := &ListOrganizationalUnitsForParentMock{
+		ListOrganizationalUnitsForParentFunc: func(
+			ctx context.Context,
+			params *organizations.ListOrganizationalUnitsForParentInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListOrganizationalUnitsForParentOutput,
+			error,
+		) {
+			switch *params.ParentId {
+			case "r-1234":
+				return &organizations.ListOrganizationalUnitsForParentOutput{
+					OrganizationalUnits: []types.OrganizationalUnit{
+						{Id: aws.String("ou-fail"), Name: aws.String("Fail")},
+						{Id: aws.String("ou-slow"), Name: aws.String("Slow")},
+					},
+				}, nil
+			case "ou-fail":
+				return nil, fmt.Errorf("Testing Error")
+			default:
+				// Wait for the failure to cancel this branch.
+				select {
+				case <-ctx.Done():
+					mu.Lock()
+					cancelled = true
+					mu.Unlock()
+					return nil, ctx.Err()
+				case <-time.After(5 * time.Second):
+					return &organizations.ListOrganizationalUnitsForParentOutput{}, nil
+				}
+			}
+		},
+	}
+
+	c := newCrawler(&organizationsMock{
+		ListOrganizationalUnitsForParentMock: *ouMock,
+		ListAccountsForParentMock:            *newTestAccountMock(),
+	}, Options{Retry: testRetryPolicy, Parallelism: 4})
+	tree := &OU{Id: "r-1234", Name: "Root"}
+	err := c.fillTree(context.Background(), tree)
+	require.ErrorContains(t, err, "Testing Error", "fillTree did not return the first error")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.True(t, cancelled, "fillTree did not cancel the other branches")
+}
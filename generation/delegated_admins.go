@@ -0,0 +1,188 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// --- DelegatedAdministrator --------------------------------------------------
+// DelegatedAdministrator is a struct that represents a member account that has
+// been made a delegated administrator for one or more AWS services.
+type DelegatedAdministrator struct {
+	AccountId string             `json:"accountId"`
+	Name      string             `json:"name"`
+	Email     string             `json:"email"`
+	Services  []DelegatedService `json:"services"`
+}
+
+// --- DelegatedService --------------------------------------------------------
+// DelegatedService is a struct that represents a service that an account is a
+// delegated administrator for.
+type DelegatedService struct {
+	ServicePrincipal      string     `json:"servicePrincipal"`
+	DelegationEnabledDate *time.Time `json:"delegationEnabledDate,omitempty"`
+}
+
+// --- TrustedService ----------------------------------------------------------
+// TrustedService is a struct that represents an AWS service that has been
+// given trusted access to the organization.
+type TrustedService struct {
+	ServicePrincipal string     `json:"servicePrincipal"`
+	DateEnabled      *time.Time `json:"dateEnabled,omitempty"`
+}
+
+// fillDelegatedAdministrators gets the delegated administrators and the
+// services with trusted access to the organization, and annotates each of the
+// delegated administrator accounts in the tree with its services.
+func (c *crawler) fillDelegatedAdministrators(ctx context.Context, org *Organization) error {
+	var admins []types.DelegatedAdministrator
+	err := parallel(ctx,
+		func(ctx context.Context) error {
+			return c.limit(ctx, func() (err error) {
+				admins, err = getDelegatedAdministrators(ctx, c.api, c.retry)
+				return err
+			})
+		},
+		func(ctx context.Context) error {
+			return c.limit(ctx, func() (err error) {
+				org.TrustedServices, err = getTrustedServices(ctx, c.api, c.retry)
+				return err
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Get the services of each delegated administrator at the same time.
+	org.DelegatedAdministrators = make([]DelegatedAdministrator, len(admins))
+	fns := make([]func(context.Context) error, len(admins))
+	for i := range admins {
+		admin := &org.DelegatedAdministrators[i]
+		admin.AccountId = aws.ToString(admins[i].Id)
+		admin.Name = aws.ToString(admins[i].Name)
+		admin.Email = aws.ToString(admins[i].Email)
+		fns[i] = func(ctx context.Context) error {
+			return c.limit(ctx, func() (err error) {
+				admin.Services, err = getDelegatedServices(ctx, c.api, c.retry, admin.AccountId)
+				return err
+			})
+		}
+	}
+	err = parallel(ctx, fns...)
+	if err != nil {
+		return err
+	}
+
+	// Annotate the accounts in the tree.
+	services := make(map[string][]string, len(org.DelegatedAdministrators))
+	for _, admin := range org.DelegatedAdministrators {
+		for _, service := range admin.Services {
+			services[admin.AccountId] = append(services[admin.AccountId], service.ServicePrincipal)
+		}
+	}
+	annotateDelegatedServices(org.Root, services)
+	return nil
+}
+
+// annotateDelegatedServices sets the delegated services of every account in the
+// tree from the map of account ID to service principals.
+func annotateDelegatedServices(ou *OU, services map[string][]string) {
+	for i := range ou.Accounts {
+		ou.Accounts[i].DelegatedServices = services[aws.ToString(ou.Accounts[i].Id)]
+	}
+	for _, child := range ou.Children {
+		annotateDelegatedServices(child, services)
+	}
+}
+
+// getDelegatedAdministrators gets every account in the organization that is a
+// delegated administrator, following the NextToken of each response until
+// every page has been read.
+func getDelegatedAdministrators(ctx context.Context, api ListDelegatedAdministrators, retry RetryPolicy) ([]types.DelegatedAdministrator, error) {
+	admins := make([]types.DelegatedAdministrator, 0)
+	var nextToken *string
+	for {
+		page, err := withRetry(ctx, retry, func() (*organizations.ListDelegatedAdministratorsOutput, error) {
+			return api.ListDelegatedAdministrators(ctx, &organizations.ListDelegatedAdministratorsInput{
+				NextToken: nextToken,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get delegated administrators: %w", err)
+		}
+		admins = append(admins, page.DelegatedAdministrators...)
+
+		// Stop once the API reports there are no more pages.
+		if page.NextToken == nil || *page.NextToken == "" {
+			return admins, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// getDelegatedServices gets the services that the account is a delegated
+// administrator for, following the NextToken of each response until every page
+// has been read.
+func getDelegatedServices(ctx context.Context, api ListDelegatedServicesForAccount, retry RetryPolicy, accountId string) ([]DelegatedService, error) {
+	services := make([]DelegatedService, 0)
+	var nextToken *string
+	for {
+		page, err := withRetry(ctx, retry, func() (*organizations.ListDelegatedServicesForAccountOutput, error) {
+			return api.ListDelegatedServicesForAccount(ctx, &organizations.ListDelegatedServicesForAccountInput{
+				AccountId: &accountId,
+				NextToken: nextToken,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get delegated services for account %s: %w", accountId, err)
+		}
+		for _, service := range page.DelegatedServices {
+			services = append(services, DelegatedService{
+				ServicePrincipal:      aws.ToString(service.ServicePrincipal),
+				DelegationEnabledDate: service.DelegationEnabledDate,
+			})
+		}
+
+		// Stop once the API reports there are no more pages.
+		if page.NextToken == nil || *page.NextToken == "" {
+			return services, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// getTrustedServices gets the AWS services that have trusted access to the
+// organization, following the NextToken of each response until every page has
+// been read.
+func getTrustedServices(ctx context.Context, api ListAWSServiceAccessForOrganization, retry RetryPolicy) ([]TrustedService, error) {
+	services := make([]TrustedService, 0)
+	var nextToken *string
+	for {
+		page, err := withRetry(ctx, retry, func() (*organizations.ListAWSServiceAccessForOrganizationOutput, error) {
+			return api.ListAWSServiceAccessForOrganization(ctx, &organizations.ListAWSServiceAccessForOrganizationInput{
+				NextToken: nextToken,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trusted service access: %w", err)
+		}
+		for _, service := range page.EnabledServicePrincipals {
+			services = append(services, TrustedService{
+				ServicePrincipal: aws.ToString(service.ServicePrincipal),
+				DateEnabled:      service.DateEnabled,
+			})
+		}
+
+		// Stop once the API reports there are no more pages.
+		if page.NextToken == nil || *page.NextToken == "" {
+			return services, nil
+		}
+		nextToken = page.NextToken
+	}
+}
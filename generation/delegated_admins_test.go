@@ -0,0 +1,165 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDelegatedAdminsMock creates an organizationsMock whose delegated
+// administrator calls return two admins, the first over two pages, and two
+// trusted services.
+func newTestDelegatedAdminsMock() *organizationsMock {
+	return &organizationsMock{
+		ListDelegatedAdministratorsMock: ListDelegatedAdministratorsMock{
+			ListDelegatedAdministratorsFunc: func(
+				ctx context.Context,
+				params *organizations.ListDelegatedAdministratorsInput,
+				optFns ...func(*organizations.Options),
+			) (
+				*organizations.ListDelegatedAdministratorsOutput,
+				error,
+			) {
+				if params.NextToken == nil {
+					return &organizations.ListDelegatedAdministratorsOutput{
+						DelegatedAdministrators: []types.DelegatedAdministrator{
+							{Id: aws.String("222222222222"), Name: aws.String("Security"), Email: aws.String("security@example.com")},
+						},
+						NextToken: aws.String("page-2"),
+					}, nil
+				}
+				return &organizations.ListDelegatedAdministratorsOutput{
+					DelegatedAdministrators: []types.DelegatedAdministrator{
+						{Id: aws.String("333333333333"), Name: aws.String("Logging")},
+					},
+				}, nil
+			},
+		},
+		ListDelegatedServicesForAccountMock: ListDelegatedServicesForAccountMock{
+			ListDelegatedServicesForAccountFunc: func(
+				ctx context.Context,
+				params *organizations.ListDelegatedServicesForAccountInput,
+				optFns ...func(*organizations.Options),
+			) (
+				*organizations.ListDelegatedServicesForAccountOutput,
+				error,
+			) {
+				switch *params.AccountId {
+				case "222222222222":
+					return &organizations.ListDelegatedServicesForAccountOutput{
+						DelegatedServices: []types.DelegatedService{
+							{ServicePrincipal: aws.String("guardduty.amazonaws.com")},
+							{ServicePrincipal: aws.String("securityhub.amazonaws.com")},
+						},
+					}, nil
+				case "333333333333":
+					return &organizations.ListDelegatedServicesForAccountOutput{
+						DelegatedServices: []types.DelegatedService{
+							{ServicePrincipal: aws.String("config.amazonaws.com")},
+						},
+					}, nil
+				}
+				return nil, fmt.Errorf("unexpected account ID %s", *params.AccountId)
+			},
+		},
+		ListAWSServiceAccessForOrganizationMock: ListAWSServiceAccessForOrganizationMock{
+			ListAWSServiceAccessForOrganizationFunc: func(
+				ctx context.Context,
+				params *organizations.ListAWSServiceAccessForOrganizationInput,
+				optFns ...func(*organizations.Options),
+			) (
+				*organizations.ListAWSServiceAccessForOrganizationOutput,
+				error,
+			) {
+				return &organizations.ListAWSServiceAccessForOrganizationOutput{
+					EnabledServicePrincipals: []types.EnabledServicePrincipal{
+						{ServicePrincipal: aws.String("guardduty.amazonaws.com")},
+						{ServicePrincipal: aws.String("config.amazonaws.com")},
+					},
+				}, nil
+			},
+		},
+	}
+}
+
+// TestFillDelegatedAdministrators tests that the delegated administrators and
+// trusted services are added to the organization and the accounts annotated.
+func TestFillDelegatedAdministrators(t *testing.T) {
+	org := &Organization{
+		Root: &OU{
+			Id:   "r-1234",
+			Name: "Root",
+			Accounts: []Account{
+				{Account: types.Account{Id: aws.String("111111111111")}},
+			},
+			Children: []*OU{
+				{
+					Id:   "ou-1",
+					Name: "Security",
+					Accounts: []Account{
+						{Account: types.Account{Id: aws.String("222222222222")}},
+						{Account: types.Account{Id: aws.String("333333333333")}},
+					},
+				},
+			},
+		},
+	}
+
+	c := newCrawler(newTestDelegatedAdminsMock(), Options{Retry: testRetryPolicy})
+	err := c.fillDelegatedAdministrators(context.Background(), org)
+	require.NoError(t, err)
+
+	// The organization has every admin and trusted service.
+	require.Equal(t, []TrustedService{
+		{ServicePrincipal: "guardduty.amazonaws.com"},
+		{ServicePrincipal: "config.amazonaws.com"},
+	}, org.TrustedServices)
+	require.Equal(t, []DelegatedAdministrator{
+		{
+			AccountId: "222222222222",
+			Name:      "Security",
+			Email:     "security@example.com",
+			Services: []DelegatedService{
+				{ServicePrincipal: "guardduty.amazonaws.com"},
+				{ServicePrincipal: "securityhub.amazonaws.com"},
+			},
+		},
+		{
+			AccountId: "333333333333",
+			Name:      "Logging",
+			Services: []DelegatedService{
+				{ServicePrincipal: "config.amazonaws.com"},
+			},
+		},
+	}, org.DelegatedAdministrators)
+
+	// The accounts in the tree are annotated.
+	require.Nil(t, org.Root.Accounts[0].DelegatedServices)
+	require.Equal(t, []string{"guardduty.amazonaws.com", "securityhub.amazonaws.com"}, org.Root.Children[0].Accounts[0].DelegatedServices)
+	require.Equal(t, []string{"config.amazonaws.com"}, org.Root.Children[0].Accounts[1].DelegatedServices)
+}
+
+// TestFillDelegatedAdministratorsError tests that an error getting the services
+// of an admin is returned.
+func TestFillDelegatedAdministratorsError(t *testing.T) {
+	api := newTestDelegatedAdminsMock()
+	api.ListDelegatedServicesForAccountMock.ListDelegatedServicesForAccountFunc = func(
+		ctx context.Context,
+		params *organizations.ListDelegatedServicesForAccountInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListDelegatedServicesForAccountOutput,
+		error,
+	) {
+		return nil, fmt.Errorf("Testing Error")
+	}
+
+	c := newCrawler(api, Options{Retry: testRetryPolicy})
+	err := c.fillDelegatedAdministrators(context.Background(), &Organization{Root: &OU{}})
+	require.ErrorContains(t, err, "Testing Error")
+}
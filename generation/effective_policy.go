@@ -0,0 +1,163 @@
+package generation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// --- EffectivePolicy ---------------------------------------------------------
+// EffectivePolicy is a struct that represents the policy of a single type that
+// applies to an account once everything it inherits has been taken into
+// account.
+type EffectivePolicy struct {
+	Type types.PolicyType `json:"type"`
+	// Content is the merged policy document returned by
+	// DescribeEffectivePolicy, it is empty for service control policies as
+	// AWS doesn't merge them into a single document.
+	Content string `json:"content,omitempty"`
+	// Sources is every policy that contributes to the effective policy, from
+	// the root down to the account itself.
+	Sources []PolicySource `json:"sources"`
+}
+
+// --- PolicySource ------------------------------------------------------------
+// PolicySource is a struct that represents a policy contributing to an
+// effective policy, along with the target it is attached to.
+type PolicySource struct {
+	PolicyId   string `json:"policyId"`
+	PolicyName string `json:"policyName"`
+	TargetId   string `json:"targetId"`
+	TargetName string `json:"targetName"`
+	// Path is the chain of names from the root to the target the policy is
+	// attached to, including the target itself.
+	Path []string `json:"path"`
+}
+
+// ancestor is a target above an account in the tree, along with the path of
+// names from the root to it.
+type ancestor struct {
+	id       string
+	name     string
+	path     []string
+	policies Policies
+}
+
+// fillEffectivePolicies sets the effective policies of every account in the
+// tree for each of the crawler's policy types. It must be called after
+// fillTree as the attachments of each ancestor are used to find the sources.
+func (c *crawler) fillEffectivePolicies(ctx context.Context, tree *OU) error {
+	fns := make([]func(context.Context) error, 0)
+
+	// Walk the tree, keeping track of the ancestors of the current OU.
+	var walk func(ou *OU, ancestors []ancestor)
+	walk = func(ou *OU, ancestors []ancestor) {
+		path := []string{ou.Name}
+		if len(ancestors) > 0 {
+			path = appendPath(ancestors[len(ancestors)-1].path, ou.Name)
+		}
+		ancestors = append(ancestors[:len(ancestors):len(ancestors)], ancestor{
+			id:       ou.Id,
+			name:     ou.Name,
+			path:     path,
+			policies: ou.Policies,
+		})
+
+		for i := range ou.Accounts {
+			account := &ou.Accounts[i]
+			chain := append(ancestors[:len(ancestors):len(ancestors)], ancestor{
+				id:       aws.ToString(account.Id),
+				name:     aws.ToString(account.Name),
+				path:     appendPath(path, aws.ToString(account.Name)),
+				policies: account.Policies,
+			})
+			fns = append(fns, func(ctx context.Context) (err error) {
+				account.EffectivePolicies, err = c.getEffectivePolicies(ctx, chain)
+				return err
+			})
+		}
+		for _, child := range ou.Children {
+			walk(child, ancestors)
+		}
+	}
+	walk(tree, nil)
+
+	return parallel(ctx, fns...)
+}
+
+// getEffectivePolicies gets the effective policy of each of the crawler's
+// policy types for the account at the end of the chain. Service control
+// policies are worked out from the chain alone, the other types are also
+// described to get the merged policy document.
+func (c *crawler) getEffectivePolicies(ctx context.Context, chain []ancestor) (map[types.PolicyType]EffectivePolicy, error) {
+	accountId := chain[len(chain)-1].id
+	effective := make(map[types.PolicyType]EffectivePolicy, len(c.policyTypes))
+	for _, policyType := range c.policyTypes {
+		policy := EffectivePolicy{
+			Type:    policyType,
+			Sources: policySources(chain, policyType),
+		}
+		if policyType != types.PolicyTypeServiceControlPolicy {
+			err := c.limit(ctx, func() (err error) {
+				policy.Content, err = getEffectivePolicyContent(ctx, c.api, c.retry, accountId, policyType)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		effective[policyType] = policy
+	}
+	return effective, nil
+}
+
+// policySources returns each policy of the given type attached to a target in
+// the chain, in order from the root down to the account.
+func policySources(chain []ancestor, policyType types.PolicyType) []PolicySource {
+	sources := make([]PolicySource, 0)
+	for _, target := range chain {
+		for _, policy := range target.policies[policyType] {
+			sources = append(sources, PolicySource{
+				PolicyId:   policy.Id,
+				PolicyName: policy.Name,
+				TargetId:   target.id,
+				TargetName: target.name,
+				Path:       target.path,
+			})
+		}
+	}
+	return sources
+}
+
+// appendPath returns a copy of the path with the name added to the end, so
+// that paths shared between siblings are never modified.
+func appendPath(path []string, name string) []string {
+	return append(path[:len(path):len(path)], name)
+}
+
+// getEffectivePolicyContent gets the merged policy document of the given type
+// that applies to the target. It returns an empty string if no policy of that
+// type applies.
+func getEffectivePolicyContent(ctx context.Context, api DescribeEffectivePolicy, retry RetryPolicy, targetId string, policyType types.PolicyType) (string, error) {
+	output, err := withRetry(ctx, retry, func() (*organizations.DescribeEffectivePolicyOutput, error) {
+		return api.DescribeEffectivePolicy(ctx, &organizations.DescribeEffectivePolicyInput{
+			PolicyType: types.EffectivePolicyType(policyType),
+			TargetId:   &targetId,
+		})
+	})
+	if err != nil {
+		var notFound *types.EffectivePolicyNotFoundException
+		if errors.As(err, &notFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to describe the effective %s for %s: %w", policyType, targetId, err)
+	}
+	if output.EffectivePolicy == nil {
+		return "", nil
+	}
+	return aws.ToString(output.EffectivePolicy.PolicyContent), nil
+}
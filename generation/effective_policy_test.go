@@ -0,0 +1,146 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEffectivePolicyMock creates a DescribeEffectivePolicyMock that returns
+// a document naming the target and type, or EffectivePolicyNotFoundException
+// for targets starting "none".
+func newTestEffectivePolicyMock() *DescribeEffectivePolicyMock {
+	return &DescribeEffectivePolicyMock{
+		DescribeEffectivePolicyFunc: func(
+			ctx context.Context,
+			params *organizations.DescribeEffectivePolicyInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.DescribeEffectivePolicyOutput,
+			error,
+		) {
+			if *params.TargetId == "none" {
+				return nil, &types.EffectivePolicyNotFoundException{}
+			}
+			if *params.TargetId == "fail" {
+				return nil, fmt.Errorf("Testing Error")
+			}
+			return &organizations.DescribeEffectivePolicyOutput{
+				EffectivePolicy: &types.EffectivePolicy{
+					PolicyContent: aws.String(fmt.Sprintf("%s:%s", params.PolicyType, *params.TargetId)),
+				},
+			}, nil
+		},
+	}
+}
+
+// TestGetEffectivePolicyContent tests the getEffectivePolicyContent function
+// returns the document, or nothing if no policy applies.
+func TestGetEffectivePolicyContent(t *testing.T) {
+	mockClient := newTestEffectivePolicyMock()
+	ctx := context.Background()
+
+	content, err := getEffectivePolicyContent(ctx, mockClient, testRetryPolicy, "123456789012", types.PolicyTypeTagPolicy)
+	require.NoError(t, err)
+	require.Equal(t, "TAG_POLICY:123456789012", content)
+
+	content, err = getEffectivePolicyContent(ctx, mockClient, testRetryPolicy, "none", types.PolicyTypeTagPolicy)
+	require.NoError(t, err)
+	require.Equal(t, "", content)
+
+	_, err = getEffectivePolicyContent(ctx, mockClient, testRetryPolicy, "fail", types.PolicyTypeTagPolicy)
+	require.Error(t, err)
+}
+
+// TestFillEffectivePolicies tests that the effective policies of each account
+// are worked out from the policies attached to its ancestors.
+func TestFillEffectivePolicies(t *testing.T) {
+	tree := &OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Policies: Policies{
+			types.PolicyTypeServiceControlPolicy: {{Id: "p-full", Name: "FullAWSAccess"}},
+			types.PolicyTypeTagPolicy:            {{Id: "p-tag", Name: "Tags"}},
+		},
+		Accounts: []Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management")}},
+		},
+		Children: []*OU{
+			{
+				Id:   "ou-1",
+				Name: "Workloads",
+				Policies: Policies{
+					types.PolicyTypeServiceControlPolicy: {{Id: "p-deny", Name: "DenyRegions"}},
+				},
+				Children: []*OU{
+					{
+						Id:   "ou-2",
+						Name: "Prod",
+						Accounts: []Account{
+							{
+								Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Prod 1")},
+								Policies: Policies{
+									types.PolicyTypeServiceControlPolicy: {{Id: "p-account", Name: "AccountOnly"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	api := &organizationsMock{DescribeEffectivePolicyMock: *newTestEffectivePolicyMock()}
+	c := newCrawler(api, Options{
+		Retry:       testRetryPolicy,
+		PolicyTypes: []types.PolicyType{types.PolicyTypeServiceControlPolicy, types.PolicyTypeTagPolicy},
+	})
+	err := c.fillEffectivePolicies(context.Background(), tree)
+	require.NoError(t, err)
+
+	// The account in the root only inherits from the root.
+	management := tree.Accounts[0].EffectivePolicies
+	require.Len(t, management, 2)
+	require.Equal(t, []PolicySource{
+		{PolicyId: "p-full", PolicyName: "FullAWSAccess", TargetId: "r-1234", TargetName: "Root", Path: []string{"Root"}},
+	}, management[types.PolicyTypeServiceControlPolicy].Sources)
+	require.Equal(t, "", management[types.PolicyTypeServiceControlPolicy].Content)
+	require.Equal(t, "TAG_POLICY:111111111111", management[types.PolicyTypeTagPolicy].Content)
+
+	// The nested account inherits from every level, in order.
+	prod := tree.Children[0].Children[0].Accounts[0].EffectivePolicies
+	require.Equal(t, []PolicySource{
+		{PolicyId: "p-full", PolicyName: "FullAWSAccess", TargetId: "r-1234", TargetName: "Root", Path: []string{"Root"}},
+		{PolicyId: "p-deny", PolicyName: "DenyRegions", TargetId: "ou-1", TargetName: "Workloads", Path: []string{"Root", "Workloads"}},
+		{PolicyId: "p-account", PolicyName: "AccountOnly", TargetId: "222222222222", TargetName: "Prod 1", Path: []string{"Root", "Workloads", "Prod", "Prod 1"}},
+	}, prod[types.PolicyTypeServiceControlPolicy].Sources)
+	require.Equal(t, []PolicySource{
+		{PolicyId: "p-tag", PolicyName: "Tags", TargetId: "r-1234", TargetName: "Root", Path: []string{"Root"}},
+	}, prod[types.PolicyTypeTagPolicy].Sources)
+	require.Equal(t, "TAG_POLICY:222222222222", prod[types.PolicyTypeTagPolicy].Content)
+}
+
+// TestFillEffectivePoliciesError tests that an error describing an effective
+// policy is returned.
+func TestFillEffectivePoliciesError(t *testing.T) {
+	tree := &OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []Account{
+			{Account: types.Account{Id: aws.String("fail"), Name: aws.String("Failing")}},
+		},
+	}
+
+	api := &organizationsMock{DescribeEffectivePolicyMock: *newTestEffectivePolicyMock()}
+	c := newCrawler(api, Options{
+		Retry:       testRetryPolicy,
+		PolicyTypes: []types.PolicyType{types.PolicyTypeBackupPolicy},
+	})
+	err := c.fillEffectivePolicies(context.Background(), tree)
+	require.Error(t, err)
+}
@@ -4,8 +4,8 @@
 // visualisation application.
 //
 // This package has a single exported function, GenerateStructure, which takes in
-// an Organizations Client and returns a custom tree structure that contains all
-// the information about the organization.
+// an Organizations Client and returns the details of the organization with a
+// custom tree structure that contains all the information about it.
 
 // It also provides some helper functions for the struct to make it easier to
 // work with.
@@ -18,34 +18,116 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
 )
 
-// GenerateStructure takes in an Organizations Client and returns a custom tree
-// structure that contains all the information about the organization.
-func GenerateStructure(ctx context.Context, orgClient *organizations.Client) (*OU, error) {
+// DefaultParallelism is the number of API calls GenerateStructure will have in
+// flight at once if no other limit is given in the Options.
+const DefaultParallelism = 8
+
+// Options holds the settings that control how GenerateStructure crawls the
+// organization.
+type Options struct {
+	// Parallelism is the maximum number of API calls made at the same time.
+	Parallelism int
+	// Retry controls how throttled API calls are retried, any unset fields
+	// are taken from the DefaultRetryPolicy.
+	Retry RetryPolicy
+	// PolicyTypes is the types of policy to fetch for the root and for every
+	// OU and account. Types that aren't enabled on the root are skipped.
+	PolicyTypes []types.PolicyType
+	// EffectivePolicies works out the effective policy of each of the
+	// PolicyTypes for every account, along with where each part of it is
+	// inherited from.
+	EffectivePolicies bool
+	// IncludeTags fetches the tags on the root and on every OU and account.
+	IncludeTags bool
+	// IncludeDelegatedAdministrators fetches the delegated administrators and
+	// the services with trusted access to the organization.
+	IncludeDelegatedAdministrators bool
+}
+
+// GenerateStructure takes in an Organizations Client and returns the details
+// of the organization along with a custom tree structure that contains all the
+// information about it, starting at the root. The OUs and accounts are
+// requested concurrently, and the crawl is cancelled as soon as any part of it
+// fails.
+func GenerateStructure(ctx context.Context, orgClient *organizations.Client, opts Options) (*Organization, error) {
+	return generateStructure(ctx, orgClient, opts)
+}
+
+// generateStructure does the work of GenerateStructure using any
+// implementation of the API, which allows it to be tested with mocks.
+func generateStructure(ctx context.Context, api organizationsAPI, opts Options) (*Organization, error) {
+	// Get the details of the organization
+	org, err := describeOrganization(ctx, api, opts.Retry)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the root of the organization
-	rootId, err := getRootId(ctx, orgClient)
+	root, err := getRoot(ctx, api, opts.Retry)
 	if err != nil {
 		return nil, err
 	}
+	org.EnabledPolicyTypes = policyTypeSummaries(root.PolicyTypes)
+	opts.PolicyTypes = enabledPolicyTypes(opts.PolicyTypes, org.EnabledPolicyTypes)
 
 	// Initialise the tree
-	tree := &OU{
-		Id:       rootId,
+	org.Root = &OU{
+		Id:       *root.Id,
 		Name:     "Root",
 		Children: []*OU{},
-		Accounts: []types.Account{},
+		Accounts: []Account{},
 	}
 
-	// Get the OUs
-	err = tree.fillOuTree(ctx, orgClient)
+	// Get the OUs, the accounts and their policies and tags
+	c := newCrawler(api, opts)
+	err = c.fillTree(ctx, org.Root)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get the accounts
-	tree, err = tree.fillAccountsRecursive(ctx, orgClient)
-	if err != nil {
-		return nil, err
+	// Get the delegated administrators and trusted services
+	if opts.IncludeDelegatedAdministrators {
+		err = c.fillDelegatedAdministrators(ctx, org)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Work out the effective policies of the accounts
+	if opts.EffectivePolicies {
+		err = c.fillEffectivePolicies(ctx, org.Root)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return tree, nil
+	return org, nil
+}
+
+// policyTypeSummaries converts the policy types returned by ListRoots into the
+// PolicyTypeSummary used in the tree.
+func policyTypeSummaries(summaries []types.PolicyTypeSummary) []PolicyTypeSummary {
+	policyTypes := make([]PolicyTypeSummary, len(summaries))
+	for i, summary := range summaries {
+		policyTypes[i] = PolicyTypeSummary{
+			Type:   summary.Type,
+			Status: summary.Status,
+		}
+	}
+	return policyTypes
+}
+
+// enabledPolicyTypes returns the requested policy types that are enabled on the
+// root, there is no point asking for policies of a type that can't be attached.
+func enabledPolicyTypes(requested []types.PolicyType, root []PolicyTypeSummary) []types.PolicyType {
+	enabled := make([]types.PolicyType, 0, len(requested))
+	for _, policyType := range requested {
+		for _, summary := range root {
+			if summary.Type == policyType && summary.Status == types.PolicyTypeStatusEnabled {
+				enabled = append(enabled, policyType)
+				break
+			}
+		}
+	}
+	return enabled
 }
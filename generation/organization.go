@@ -0,0 +1,56 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// --- Organization ------------------------------------------------------------
+// Organization is a struct that represents the whole AWS Organization. It is
+// the top level document of the structure, holding the details of the
+// organization along with the tree of OUs starting at the root.
+type Organization struct {
+	Id                     string                       `json:"id"`
+	Arn                    string                       `json:"arn"`
+	ManagementAccountId    string                       `json:"managementAccountId"`
+	ManagementAccountEmail string                       `json:"managementAccountEmail"`
+	FeatureSet             types.OrganizationFeatureSet `json:"featureSet"`
+	// EnabledPolicyTypes is the status of each policy type on the root.
+	EnabledPolicyTypes []PolicyTypeSummary `json:"enabledPolicyTypes"`
+	// DelegatedAdministrators and TrustedServices are only set if they were
+	// requested when generating the structure.
+	DelegatedAdministrators []DelegatedAdministrator `json:"delegatedAdministrators,omitempty"`
+	TrustedServices         []TrustedService         `json:"trustedServices,omitempty"`
+	Root                    *OU                      `json:"root"`
+}
+
+// ToJSON returns a JSON representation of the Organization.
+func (o *Organization) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(o, "", "  ")
+}
+
+// describeOrganization gets the details of the organization from the
+// DescribeOrganization API, the root and policy types are filled in later.
+func describeOrganization(ctx context.Context, api DescribeOrganization, retry RetryPolicy) (*Organization, error) {
+	output, err := withRetry(ctx, retry, func() (*organizations.DescribeOrganizationOutput, error) {
+		return api.DescribeOrganization(ctx, &organizations.DescribeOrganizationInput{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the organization: %w", err)
+	}
+	if output.Organization == nil {
+		return nil, fmt.Errorf("failed to describe the organization: no organization returned")
+	}
+	return &Organization{
+		Id:                     aws.ToString(output.Organization.Id),
+		Arn:                    aws.ToString(output.Organization.Arn),
+		ManagementAccountId:    aws.ToString(output.Organization.MasterAccountId),
+		ManagementAccountEmail: aws.ToString(output.Organization.MasterAccountEmail),
+		FeatureSet:             output.Organization.FeatureSet,
+	}, nil
+}
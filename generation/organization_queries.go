@@ -3,82 +3,87 @@ package generation
 import (
 	"context"
 	"fmt"
-	"strings"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
 )
 
-func getOUsForParent(ctx context.Context, api ListOrganizationalUnitsForParent, parentId string) ([]*OU, error) {
-	// Retry 5 times if the API call fails due to rate limits
-	for i := 0; i < 5; i++ {
-		// Get the child OUs of the root OU.
-		ouList, err := api.ListOrganizationalUnitsForParent(ctx, &organizations.ListOrganizationalUnitsForParentInput{
-			ParentId: &parentId,
+// getOUsForParent gets all the child OUs of the given parent, following the
+// NextToken of each response until every page has been read.
+func getOUsForParent(ctx context.Context, api ListOrganizationalUnitsForParent, retry RetryPolicy, parentId string) ([]*OU, error) {
+	ous := make([]*OU, 0)
+	var nextToken *string
+	for {
+		// Get a page of the child OUs of the parent OU, retrying if throttled.
+		page, err := withRetry(ctx, retry, func() (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+			return api.ListOrganizationalUnitsForParent(ctx, &organizations.ListOrganizationalUnitsForParentInput{
+				ParentId:  &parentId,
+				NextToken: nextToken,
+			})
 		})
 		if err != nil {
-			if strings.Contains(err.Error(), "exceeded maximum number of attempts") {
-				time.Sleep(5 * time.Second)
-				continue
-			}
-			return nil, err
+			return nil, fmt.Errorf("failed to get OUs for parent %s: %w", parentId, err)
 		}
-		ous := make([]*OU, len(ouList.OrganizationalUnits))
-		for i, ou := range ouList.OrganizationalUnits {
-			ous[i] = &OU{
+		for _, ou := range page.OrganizationalUnits {
+			ous = append(ous, &OU{
 				Id:   *ou.Id,
 				Name: *ou.Name,
-			}
+			})
 		}
-		return ous, nil
-	}
-	return nil, fmt.Errorf("failed to get OUs for parent %s, most likely due to rate limits", parentId)
-}
 
-// getRootID gets the ID of the root OU.
-func getRootId(ctx context.Context, api ListRoots) (string, error) {
-	// Retry 5 times if the API call fails due to rate limits
-	for i := 0; i < 5; i++ {
-		// Get the root OU id.
-		rootOU, err := api.ListRoots(ctx, &organizations.ListRootsInput{})
-		if err != nil {
-			if strings.Contains(err.Error(), "exceeded maximum number of attempts") {
-				time.Sleep(5 * time.Second)
-				continue
-			}
-			return "", err
+		// Stop once the API reports there are no more pages.
+		if page.NextToken == nil || *page.NextToken == "" {
+			return ous, nil
 		}
+		nextToken = page.NextToken
+	}
+}
 
-		return *rootOU.Roots[0].Id, nil
+// getRoot gets the root of the organization.
+func getRoot(ctx context.Context, api ListRoots, retry RetryPolicy) (types.Root, error) {
+	// Get the root OU, retrying if throttled.
+	roots, err := withRetry(ctx, retry, func() (*organizations.ListRootsOutput, error) {
+		return api.ListRoots(ctx, &organizations.ListRootsInput{})
+	})
+	if err != nil {
+		return types.Root{}, fmt.Errorf("failed to get root OU: %w", err)
+	}
+	if len(roots.Roots) == 0 {
+		return types.Root{}, fmt.Errorf("failed to get root OU: no roots returned")
 	}
-	return "", fmt.Errorf("failed to get root OU ID, most likely due to rate limits")
+
+	return roots.Roots[0], nil
 }
 
 // GetAccountsFromOU gets a list of aws accounts from an OU name.
-func getAccountsFromOU(ctx context.Context, svc *organizations.Client, ouId string, ouBlock string) ([]types.Account, error) {
+func getAccountsFromOU(ctx context.Context, svc ListAccountsForParent, retry RetryPolicy, ouId string, ouBlock string) ([]types.Account, error) {
 	// Get the child accounts of the parameter OU.
 	paginator := organizations.NewListAccountsForParentPaginator(svc, &organizations.ListAccountsForParentInput{
 		ParentId: &ouId,
 	})
-	accounts, err := getAllAccountsFromOUID(ctx, paginator, ouId)
+	accounts, err := getAllAccountsFromOUID(ctx, paginator, retry, ouId)
 	if err != nil {
 		return nil, err
 	}
 	return accounts, nil
 }
 
-// getAllAccountsFromOUID gets all the accounts from an OU ID.
-func getAllAccountsFromOUID(ctx context.Context, svc ListAccountsForParentPaginator, ou_id string) ([]types.Account, error) {
+// getAllAccountsFromOUID gets all the accounts from an OU ID. Each page is
+// retried if throttled, the paginator only moves on once a page succeeds.
+func getAllAccountsFromOUID(ctx context.Context, svc ListAccountsForParentPaginator, retry RetryPolicy, ou_id string) ([]types.Account, error) {
+	nextPage := func() (*organizations.ListAccountsForParentOutput, error) {
+		return svc.NextPage(ctx)
+	}
+
 	// Get the child accounts of the given ou.
-	account_list, err := svc.NextPage(ctx)
+	account_list, err := withRetry(ctx, retry, nextPage)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get the next page of accounts if there is one.
 	for svc.HasMorePages() {
-		output, err := svc.NextPage(ctx)
+		output, err := withRetry(ctx, retry, nextPage)
 		if err != nil {
 			return nil, err
 		}
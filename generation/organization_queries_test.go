@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"testing"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,7 +49,7 @@ func TestGetOUsForParent(t *testing.T) {
 
 	// Call the function
 	ctx := context.Background()
-	ous, err := getOUsForParent(ctx, &mockClient, "ou-1234")
+	ous, err := getOUsForParent(ctx, &mockClient, testRetryPolicy, "ou-1234")
 	require.NoError(t, err)
 
 	// Check that the correct OU was returned
@@ -95,20 +95,111 @@ func TestGetOUsForParentError(t *testing.T) {
 
 	// Call the function
 	ctx := context.Background()
-	ous, err := getOUsForParent(ctx, &mockClient, "ou-4321")
+	ous, err := getOUsForParent(ctx, &mockClient, testRetryPolicy, "ou-4321")
 	require.Error(t, err)
 	require.Nil(t, ous)
 }
 
-// TestGetOUsForParentError tests the getOUsForParent function that it doesn't
-// loop forever if rate limits occur.
-func TestGetOUsForParentErrorRateLimit(t *testing.T) {
-	// This tests that the getOUForParent function returns the correct OUs for a
-	// given parent OU using a mock library
+// TestGetOUsForParentPaginated tests the getOUsForParent function follows the
+// NextToken of each response and returns the OUs from every page in order.
+func TestGetOUsForParentPaginated(t *testing.T) {
+	// Create the mock, each page points at the next one with its NextToken
+	pages := map[string]*organizations.ListOrganizationalUnitsForParentOutput{
+		"": {
+			OrganizationalUnits: []types.OrganizationalUnit{
+				{Id: aws.String("ou-1111"), Name: aws.String("TestOU1")},
+				{Id: aws.String("ou-2222"), Name: aws.String("TestOU2")},
+			},
+			NextToken: aws.String("page-2"),
+		},
+		"page-2": {
+			OrganizationalUnits: []types.OrganizationalUnit{
+				{Id: aws.String("ou-3333"), Name: aws.String("TestOU3")},
+			},
+			NextToken: aws.String("page-3"),
+		},
+		"page-3": {
+			OrganizationalUnits: []types.OrganizationalUnit{
+				{Id: aws.String("ou-4444"), Name: aws.String("TestOU4")},
+			},
+		},
+	}
+	calls := 0
+	mockClient := ListOrganizationalUnitsForParentMock{
+		ListOrganizationalUnitsForParentFunc: func(
+			ctx context.Context,
+			params *organizations.ListOrganizationalUnitsForParentInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListOrganizationalUnitsForParentOutput,
+			error,
+		) {
+			calls++
+			// Check that the correct parent ID was passed in on every page
+			if *params.ParentId != "ou-1234" {
+				return nil, fmt.Errorf("expected parent ID to be ou-1234, got %s", *params.ParentId)
+			}
+			token := aws.ToString(params.NextToken)
+			page, ok := pages[token]
+			if !ok {
+				return nil, fmt.Errorf("unexpected next token %s", token)
+			}
+			return page, nil
+		},
+	}
 
-	// Check current time:
-	timeBefore := time.Now()
-	// Create the mock
+	// Call the function
+	ctx := context.Background()
+	ous, err := getOUsForParent(ctx, &mockClient, testRetryPolicy, "ou-1234")
+	require.NoError(t, err)
+
+	// Check that every page was requested and the OUs are in page order
+	require.Equal(t, 3, calls)
+	require.Equal(t, 4, len(ous))
+	require.Equal(t, "ou-1111", ous[0].Id)
+	require.Equal(t, "ou-2222", ous[1].Id)
+	require.Equal(t, "ou-3333", ous[2].Id)
+	require.Equal(t, "TestOU3", ous[2].Name)
+	require.Equal(t, "ou-4444", ous[3].Id)
+}
+
+// TestGetOUsForParentPaginatedError tests the getOUsForParent function returns
+// an error rather than a partial list when a later page fails.
+func TestGetOUsForParentPaginatedError(t *testing.T) {
+	// Create the mock, the second page returns an error
+	mockClient := ListOrganizationalUnitsForParentMock{
+		ListOrganizationalUnitsForParentFunc: func(
+			ctx context.Context,
+			params *organizations.ListOrganizationalUnitsForParentInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListOrganizationalUnitsForParentOutput,
+			error,
+		) {
+			if params.NextToken != nil {
+				return nil, fmt.Errorf("Testing Error")
+			}
+			return &organizations.ListOrganizationalUnitsForParentOutput{
+				OrganizationalUnits: []types.OrganizationalUnit{
+					{Id: aws.String("ou-1111"), Name: aws.String("TestOU1")},
+				},
+				NextToken: aws.String("page-2"),
+			}, nil
+		},
+	}
+
+	// Call the function
+	ctx := context.Background()
+	ous, err := getOUsForParent(ctx, &mockClient, testRetryPolicy, "ou-1234")
+	require.Error(t, err)
+	require.Nil(t, ous)
+}
+
+// TestGetOUsForParentErrorRateLimit tests the getOUsForParent function retries
+// when throttled and doesn't loop forever if the rate limits continue.
+func TestGetOUsForParentErrorRateLimit(t *testing.T) {
+	// Create the mock, it is always throttled
+	calls := 0
 	mockClient := ListOrganizationalUnitsForParentMock{
 		ListOrganizationalUnitsForParentFunc: func(
 			ctx context.Context,
@@ -118,29 +209,74 @@ func TestGetOUsForParentErrorRateLimit(t *testing.T) {
 			*organizations.ListOrganizationalUnitsForParentOutput,
 			error,
 		) {
-			return nil, fmt.Errorf("exceeded maximum number of attempts")
+			calls++
+			return nil, &smithy.GenericAPIError{Code: "TooManyRequestsException"}
 		},
 	}
 
 	// Call the function
 	ctx := context.Background()
-	ous, err := getOUsForParent(ctx, &mockClient, "ou-4321")
+	ous, err := getOUsForParent(ctx, &mockClient, testRetryPolicy, "ou-4321")
 	require.Error(t, err)
 	require.Nil(t, ous)
 
-	// Check that the function didn't loop forever
-	timeAfter := time.Now()
+	// Check that the function gave up after the maximum number of attempts
+	require.Equal(t, testRetryPolicy.MaxAttempts, calls)
+}
 
-	// The code loops 5 times and waits 5 seconds each time, so the time taken
-	// should be less than 30 seconds
-	expectedTimeDiff := 30 * time.Second
-	require.True(t, timeAfter.Sub(timeBefore) < expectedTimeDiff)
+// TestGetAllAccountsFromOUIDRateLimit tests the getAllAccountsFromOUID function
+// retries a page that was throttled rather than failing the whole OU.
+func TestGetAllAccountsFromOUIDRateLimit(t *testing.T) {
+	// Create the mock, the first request is throttled
+	mockClient := throttledListAccountsForParentPager{
+		Throttles: 1,
+		mockListAccountsForParentPager: mockListAccountsForParentPager{
+			Pages: []organizations.ListAccountsForParentOutput{
+				{
+					Accounts: []types.Account{
+						{
+							Id:   aws.String("a-1234"),
+							Name: aws.String("TestAccount"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Call the function
+	ctx := context.Background()
+	accounts, err := getAllAccountsFromOUID(ctx, &mockClient, testRetryPolicy, "ou-1234")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(accounts))
+	require.Equal(t, "a-1234", *accounts[0].Id)
+}
+
+// throttledListAccountsForParentPager is a mockListAccountsForParentPager that
+// returns a throttling error for the first Throttles calls to NextPage.
+type throttledListAccountsForParentPager struct {
+	mockListAccountsForParentPager
+	Throttles int
+}
+
+func (m *throttledListAccountsForParentPager) NextPage(
+	ctx context.Context,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListAccountsForParentOutput,
+	error,
+) {
+	if m.Throttles > 0 {
+		m.Throttles--
+		return nil, &smithy.GenericAPIError{Code: "ThrottlingException"}
+	}
+	return m.mockListAccountsForParentPager.NextPage(ctx, optFns...)
 }
 
-// TestGetRootId tests the getRootId function calls the mock correctly and
-// returns the data correctly.
-func TestGetRootId(t *testing.T) {
-	// This tests that the getRootId function returns the correct root ID using a
+// TestGetRoot tests the getRoot function calls the mock correctly and returns
+// the data correctly.
+func TestGetRoot(t *testing.T) {
+	// This tests that the getRoot function returns the correct root using a
 	// mock library
 
 	// Create the mock
@@ -158,6 +294,12 @@ func TestGetRootId(t *testing.T) {
 				Roots: []types.Root{
 					{
 						Id: aws.String("r-1234"),
+						PolicyTypes: []types.PolicyTypeSummary{
+							{
+								Type:   types.PolicyTypeServiceControlPolicy,
+								Status: types.PolicyTypeStatusEnabled,
+							},
+						},
 					},
 				},
 			}
@@ -168,17 +310,19 @@ func TestGetRootId(t *testing.T) {
 
 	// Call the function
 	ctx := context.Background()
-	rootId, err := getRootId(ctx, &mockClient)
+	root, err := getRoot(ctx, &mockClient, testRetryPolicy)
 	require.NoError(t, err)
 
-	// Check that the correct root ID was returned
-	require.Equal(t, "r-1234", rootId)
+	// Check that the correct root was returned
+	require.Equal(t, "r-1234", *root.Id)
+	require.Len(t, root.PolicyTypes, 1)
+	require.Equal(t, types.PolicyTypeServiceControlPolicy, root.PolicyTypes[0].Type)
 }
 
-// TestGetRootIdError tests the getRootId function calls the mock correctly and
+// TestGetRootError tests the getRoot function calls the mock correctly and
 // returns the error correctly.
-func TestGetRootIdError(t *testing.T) {
-	// This tests that the getRootId function returns the correct root ID using a
+func TestGetRootError(t *testing.T) {
+	// This tests that the getRoot function returns the correct root using a
 	// mock library
 
 	// Create the mock
@@ -198,9 +342,9 @@ func TestGetRootIdError(t *testing.T) {
 
 	// Call the function
 	ctx := context.Background()
-	rootId, err := getRootId(ctx, &mockClient)
+	root, err := getRoot(ctx, &mockClient, testRetryPolicy)
 	require.Error(t, err)
-	require.Equal(t, "", rootId)
+	require.Nil(t, root.Id)
 }
 
 // TestGetAllAccountsFromOUID tests the getAccountsFromOU function calls the mock
@@ -234,7 +378,7 @@ func TestGetAllAccountsFromOUID(t *testing.T) {
 
 	// Call the function
 	ctx := context.Background()
-	accounts, err := getAllAccountsFromOUID(ctx, &mockClient, "ou-1234")
+	accounts, err := getAllAccountsFromOUID(ctx, &mockClient, testRetryPolicy, "ou-1234")
 	require.NoError(t, err)
 
 	// Check that the correct accounts were returned
@@ -269,7 +413,7 @@ func TestGetAllAccountsFromOUIDError(t *testing.T) {
 
 	// Call the function
 	ctx := context.Background()
-	accounts, err := getAllAccountsFromOUID(ctx, &mockClient, "ou-1234")
+	accounts, err := getAllAccountsFromOUID(ctx, &mockClient, testRetryPolicy, "ou-1234")
 	require.Error(t, err)
 	require.Nil(t, accounts)
 }
@@ -0,0 +1,144 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDescribeOrganizationMock creates a DescribeOrganizationMock that
+// returns a test organization.
+func newTestDescribeOrganizationMock() *DescribeOrganizationMock {
+	return &DescribeOrganizationMock{
+		DescribeOrganizationFunc: func(
+			ctx context.Context,
+			params *organizations.DescribeOrganizationInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.DescribeOrganizationOutput,
+			error,
+		) {
+			return &organizations.DescribeOrganizationOutput{
+				Organization: &types.Organization{
+					Id:                 aws.String("o-1234"),
+					Arn:                aws.String("arn:aws:organizations::111111111111:organization/o-1234"),
+					MasterAccountId:    aws.String("111111111111"),
+					MasterAccountEmail: aws.String("management@example.com"),
+					FeatureSet:         types.OrganizationFeatureSetAll,
+				},
+			}, nil
+		},
+	}
+}
+
+// TestDescribeOrganization tests the describeOrganization function copies the
+// details of the organization.
+func TestDescribeOrganization(t *testing.T) {
+	ctx := context.Background()
+	org, err := describeOrganization(ctx, newTestDescribeOrganizationMock(), testRetryPolicy)
+	require.NoError(t, err)
+	require.Equal(t, "o-1234", org.Id)
+	require.Equal(t, "arn:aws:organizations::111111111111:organization/o-1234", org.Arn)
+	require.Equal(t, "111111111111", org.ManagementAccountId)
+	require.Equal(t, "management@example.com", org.ManagementAccountEmail)
+	require.Equal(t, types.OrganizationFeatureSetAll, org.FeatureSet)
+	require.Nil(t, org.Root)
+
+	// Check that errors are returned
+	mockClient := DescribeOrganizationMock{
+		DescribeOrganizationFunc: func(
+			ctx context.Context,
+			params *organizations.DescribeOrganizationInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.DescribeOrganizationOutput,
+			error,
+		) {
+			return nil, fmt.Errorf("Testing Error")
+		},
+	}
+	_, err = describeOrganization(ctx, &mockClient, testRetryPolicy)
+	require.Error(t, err)
+}
+
+// TestGenerateStructure tests the generateStructure function puts together the
+// organization details, the enabled policy types and the tree.
+func TestGenerateStructure(t *testing.T) {
+	var inFlight, maxInFlight, describes int32
+	api := newTestOrganizationsMock(&inFlight, &maxInFlight)
+	api.DescribeOrganizationMock = *newTestDescribeOrganizationMock()
+	api.ListRootsMock = ListRootsMock{
+		ListRootsFunc: func(
+			ctx context.Context,
+			params *organizations.ListRootsInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListRootsOutput,
+			error,
+		) {
+			return &organizations.ListRootsOutput{
+				Roots: []types.Root{
+					{
+						Id: aws.String("r-1234"),
+						PolicyTypes: []types.PolicyTypeSummary{
+							{Type: types.PolicyTypeServiceControlPolicy, Status: types.PolicyTypeStatusEnabled},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+	list, describe := newTestPolicyMocks(&describes)
+	api.ListPoliciesForTargetMock = *list
+	api.DescribePolicyMock = *describe
+
+	// Tag policies aren't enabled on the root so shouldn't be requested.
+	org, err := generateStructure(context.Background(), api, Options{
+		Retry:       testRetryPolicy,
+		PolicyTypes: []types.PolicyType{types.PolicyTypeServiceControlPolicy, types.PolicyTypeTagPolicy},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "o-1234", org.Id)
+	require.Equal(t, []PolicyTypeSummary{
+		{Type: types.PolicyTypeServiceControlPolicy, Status: types.PolicyTypeStatusEnabled},
+	}, org.EnabledPolicyTypes)
+	require.Equal(t, "r-1234", org.Root.Id)
+	require.Equal(t, "Root", org.Root.Name)
+	require.Len(t, org.Root.Children, 3)
+	require.Len(t, org.Root.Children[0].Policies[types.PolicyTypeServiceControlPolicy], 2)
+	require.Len(t, org.Root.Children[0].Policies[types.PolicyTypeTagPolicy], 0)
+}
+
+// TestOrganizationToJSON tests that the details of the organization are top
+// level fields of the JSON, with the tree under the root field.
+func TestOrganizationToJSON(t *testing.T) {
+	org := &Organization{
+		Id:                     "o-1234",
+		Arn:                    "arn:aws:organizations::111111111111:organization/o-1234",
+		ManagementAccountId:    "111111111111",
+		ManagementAccountEmail: "management@example.com",
+		FeatureSet:             types.OrganizationFeatureSetAll,
+		EnabledPolicyTypes: []PolicyTypeSummary{
+			{Type: types.PolicyTypeServiceControlPolicy, Status: types.PolicyTypeStatusEnabled},
+		},
+		Root: &OU{Id: "r-1234", Name: "Root"},
+	}
+	output, err := org.ToJSON()
+	require.NoError(t, err)
+
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(output, &fields)
+	require.NoError(t, err)
+	require.Equal(t, "o-1234", fields["id"])
+	require.Equal(t, "111111111111", fields["managementAccountId"])
+	require.Equal(t, "management@example.com", fields["managementAccountEmail"])
+	require.Equal(t, "ALL", fields["featureSet"])
+	require.Len(t, fields["enabledPolicyTypes"], 1)
+	require.Equal(t, "r-1234", fields["root"].(map[string]interface{})["id"])
+}
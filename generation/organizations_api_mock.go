@@ -122,3 +122,330 @@ func (m *mockListAccountsForParentPager) NextPage(
 	m.PageNum++
 	return &page, nil
 }
+
+// --- ListAccountsForParent ---------------------------------------------------
+// ListAccountsForParent is an interface for the organizations
+// ListAccountsForParent function in the AWS SDK that allows for mocking, it is
+// used to build a ListAccountsForParentPaginator.
+type ListAccountsForParent interface {
+	ListAccountsForParent(
+		ctx context.Context,
+		params *organizations.ListAccountsForParentInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListAccountsForParentOutput,
+		error,
+	)
+}
+
+type ListAccountsForParentMock struct {
+	ListAccountsForParentFunc func(
+		ctx context.Context,
+		params *organizations.ListAccountsForParentInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListAccountsForParentOutput,
+		error,
+	)
+}
+
+func (m *ListAccountsForParentMock) ListAccountsForParent(
+	ctx context.Context,
+	params *organizations.ListAccountsForParentInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListAccountsForParentOutput,
+	error,
+) {
+	return m.ListAccountsForParentFunc(ctx, params, optFns...)
+}
+
+// --- ListPoliciesForTarget ---------------------------------------------------
+// ListPoliciesForTarget is an interface for the organizations
+// ListPoliciesForTarget function in the AWS SDK that allows for mocking.
+type ListPoliciesForTarget interface {
+	ListPoliciesForTarget(
+		ctx context.Context,
+		params *organizations.ListPoliciesForTargetInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListPoliciesForTargetOutput,
+		error,
+	)
+}
+
+type ListPoliciesForTargetMock struct {
+	ListPoliciesForTargetFunc func(
+		ctx context.Context,
+		params *organizations.ListPoliciesForTargetInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListPoliciesForTargetOutput,
+		error,
+	)
+}
+
+func (m *ListPoliciesForTargetMock) ListPoliciesForTarget(
+	ctx context.Context,
+	params *organizations.ListPoliciesForTargetInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListPoliciesForTargetOutput,
+	error,
+) {
+	return m.ListPoliciesForTargetFunc(ctx, params, optFns...)
+}
+
+// --- DescribePolicy ----------------------------------------------------------
+// DescribePolicy is an interface for the organizations DescribePolicy
+// function in the AWS SDK that allows for mocking.
+type DescribePolicy interface {
+	DescribePolicy(
+		ctx context.Context,
+		params *organizations.DescribePolicyInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DescribePolicyOutput,
+		error,
+	)
+}
+
+type DescribePolicyMock struct {
+	DescribePolicyFunc func(
+		ctx context.Context,
+		params *organizations.DescribePolicyInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DescribePolicyOutput,
+		error,
+	)
+}
+
+func (m *DescribePolicyMock) DescribePolicy(
+	ctx context.Context,
+	params *organizations.DescribePolicyInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.DescribePolicyOutput,
+	error,
+) {
+	return m.DescribePolicyFunc(ctx, params, optFns...)
+}
+
+// --- DescribeEffectivePolicy -------------------------------------------------
+// DescribeEffectivePolicy is an interface for the organizations
+// DescribeEffectivePolicy function in the AWS SDK that allows for mocking.
+type DescribeEffectivePolicy interface {
+	DescribeEffectivePolicy(
+		ctx context.Context,
+		params *organizations.DescribeEffectivePolicyInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DescribeEffectivePolicyOutput,
+		error,
+	)
+}
+
+type DescribeEffectivePolicyMock struct {
+	DescribeEffectivePolicyFunc func(
+		ctx context.Context,
+		params *organizations.DescribeEffectivePolicyInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DescribeEffectivePolicyOutput,
+		error,
+	)
+}
+
+func (m *DescribeEffectivePolicyMock) DescribeEffectivePolicy(
+	ctx context.Context,
+	params *organizations.DescribeEffectivePolicyInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.DescribeEffectivePolicyOutput,
+	error,
+) {
+	return m.DescribeEffectivePolicyFunc(ctx, params, optFns...)
+}
+
+// --- ListTagsForResource -----------------------------------------------------
+// ListTagsForResource is an interface for the organizations
+// ListTagsForResource function in the AWS SDK that allows for mocking.
+type ListTagsForResource interface {
+	ListTagsForResource(
+		ctx context.Context,
+		params *organizations.ListTagsForResourceInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListTagsForResourceOutput,
+		error,
+	)
+}
+
+type ListTagsForResourceMock struct {
+	ListTagsForResourceFunc func(
+		ctx context.Context,
+		params *organizations.ListTagsForResourceInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListTagsForResourceOutput,
+		error,
+	)
+}
+
+func (m *ListTagsForResourceMock) ListTagsForResource(
+	ctx context.Context,
+	params *organizations.ListTagsForResourceInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListTagsForResourceOutput,
+	error,
+) {
+	return m.ListTagsForResourceFunc(ctx, params, optFns...)
+}
+
+// --- DescribeOrganization ----------------------------------------------------
+// DescribeOrganization is an interface for the organizations
+// DescribeOrganization function in the AWS SDK that allows for mocking.
+type DescribeOrganization interface {
+	DescribeOrganization(
+		ctx context.Context,
+		params *organizations.DescribeOrganizationInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DescribeOrganizationOutput,
+		error,
+	)
+}
+
+type DescribeOrganizationMock struct {
+	DescribeOrganizationFunc func(
+		ctx context.Context,
+		params *organizations.DescribeOrganizationInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DescribeOrganizationOutput,
+		error,
+	)
+}
+
+func (m *DescribeOrganizationMock) DescribeOrganization(
+	ctx context.Context,
+	params *organizations.DescribeOrganizationInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.DescribeOrganizationOutput,
+	error,
+) {
+	return m.DescribeOrganizationFunc(ctx, params, optFns...)
+}
+
+// --- ListDelegatedAdministrators ---------------------------------------------
+// ListDelegatedAdministrators is an interface for the organizations
+// ListDelegatedAdministrators function in the AWS SDK that allows for mocking.
+type ListDelegatedAdministrators interface {
+	ListDelegatedAdministrators(
+		ctx context.Context,
+		params *organizations.ListDelegatedAdministratorsInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListDelegatedAdministratorsOutput,
+		error,
+	)
+}
+
+type ListDelegatedAdministratorsMock struct {
+	ListDelegatedAdministratorsFunc func(
+		ctx context.Context,
+		params *organizations.ListDelegatedAdministratorsInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListDelegatedAdministratorsOutput,
+		error,
+	)
+}
+
+func (m *ListDelegatedAdministratorsMock) ListDelegatedAdministrators(
+	ctx context.Context,
+	params *organizations.ListDelegatedAdministratorsInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListDelegatedAdministratorsOutput,
+	error,
+) {
+	return m.ListDelegatedAdministratorsFunc(ctx, params, optFns...)
+}
+
+// --- ListDelegatedServicesForAccount -----------------------------------------
+// ListDelegatedServicesForAccount is an interface for the organizations
+// ListDelegatedServicesForAccount function in the AWS SDK that allows for
+// mocking.
+type ListDelegatedServicesForAccount interface {
+	ListDelegatedServicesForAccount(
+		ctx context.Context,
+		params *organizations.ListDelegatedServicesForAccountInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListDelegatedServicesForAccountOutput,
+		error,
+	)
+}
+
+type ListDelegatedServicesForAccountMock struct {
+	ListDelegatedServicesForAccountFunc func(
+		ctx context.Context,
+		params *organizations.ListDelegatedServicesForAccountInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListDelegatedServicesForAccountOutput,
+		error,
+	)
+}
+
+func (m *ListDelegatedServicesForAccountMock) ListDelegatedServicesForAccount(
+	ctx context.Context,
+	params *organizations.ListDelegatedServicesForAccountInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListDelegatedServicesForAccountOutput,
+	error,
+) {
+	return m.ListDelegatedServicesForAccountFunc(ctx, params, optFns...)
+}
+
+// --- ListAWSServiceAccessForOrganization -------------------------------------
+// ListAWSServiceAccessForOrganization is an interface for the organizations
+// ListAWSServiceAccessForOrganization function in the AWS SDK that allows for
+// mocking.
+type ListAWSServiceAccessForOrganization interface {
+	ListAWSServiceAccessForOrganization(
+		ctx context.Context,
+		params *organizations.ListAWSServiceAccessForOrganizationInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListAWSServiceAccessForOrganizationOutput,
+		error,
+	)
+}
+
+type ListAWSServiceAccessForOrganizationMock struct {
+	ListAWSServiceAccessForOrganizationFunc func(
+		ctx context.Context,
+		params *organizations.ListAWSServiceAccessForOrganizationInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListAWSServiceAccessForOrganizationOutput,
+		error,
+	)
+}
+
+func (m *ListAWSServiceAccessForOrganizationMock) ListAWSServiceAccessForOrganization(
+	ctx context.Context,
+	params *organizations.ListAWSServiceAccessForOrganizationInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListAWSServiceAccessForOrganizationOutput,
+	error,
+) {
+	return m.ListAWSServiceAccessForOrganizationFunc(ctx, params, optFns...)
+}
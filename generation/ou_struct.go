@@ -1,10 +1,8 @@
 package generation
 
 import (
-	"context"
 	"encoding/json"
 
-	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
 )
 
@@ -13,10 +11,26 @@ import (
 // It can be used to represent the entire structure or a substructure in the
 // style of a tree.
 type OU struct {
-	Id       string          `json:"id"`
-	Name     string          `json:"name"`
-	Children []*OU           `json:"children"`
-	Accounts []types.Account `json:"accounts"`
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Children []*OU             `json:"children"`
+	Accounts []Account         `json:"accounts"`
+	Policies Policies          `json:"policies,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// --- Account -----------------------------------------------------------------
+// Account is a struct that represents an account in the AWS Organizations
+// structure. It embeds the account returned by the API so its fields are
+// marshalled to JSON as before, alongside what else is known about it.
+type Account struct {
+	types.Account
+	Policies          Policies                             `json:"policies,omitempty"`
+	Tags              map[string]string                    `json:"tags,omitempty"`
+	EffectivePolicies map[types.PolicyType]EffectivePolicy `json:"effectivePolicies,omitempty"`
+	// DelegatedServices is the service principals of the services that the
+	// account is a delegated administrator for.
+	DelegatedServices []string `json:"delegatedServices,omitempty"`
 }
 
 // addChildren adds the given OUs to the OU's children slice.
@@ -40,63 +54,55 @@ func (o *OU) GetName() string {
 }
 
 // GetAccounts returns a list of the accounts in the OU.
-func (o *OU) GetAccounts() []types.Account {
+func (o *OU) GetAccounts() []Account {
 	return o.Accounts
 }
 
-// ToJSON returns a JSON representation of the OU.
-func (o *OU) ToJSON() ([]byte, error) {
-	return json.MarshalIndent(o, "", "  ")
-}
-
-func (parent *OU) fillOuTree(ctx context.Context, api ListOrganizationalUnitsForParent) error {
-	// Get the OUs for the parent OU.
-	ous, err := getOUsForParent(ctx, api, parent.Id)
-	if err != nil {
-		return err
-	}
-	if len(ous) == 0 {
-		return nil
+// TotalAccounts returns the number of accounts in the OU and every OU below it.
+func (o *OU) TotalAccounts() int {
+	total := len(o.Accounts)
+	for _, child := range o.Children {
+		total += child.TotalAccounts()
 	}
+	return total
+}
 
-	// Recursively fill the tree with the OUs.
-	for i := range ous {
-		err := ous[i].fillOuTree(ctx, api)
-		if err != nil {
-			return err
-		}
+// TotalOUs returns the number of OUs below the OU, not counting the OU itself.
+func (o *OU) TotalOUs() int {
+	total := len(o.Children)
+	for _, child := range o.Children {
+		total += child.TotalOUs()
 	}
+	return total
+}
 
-	// Append the OUs to the parent OU.
-	parent.addChildren(ous)
-
-	return nil
+// AccountsByStatus returns the number of accounts with each status in the OU
+// and every OU below it. Statuses that no account has are left out.
+func (o *OU) AccountsByStatus() map[types.AccountStatus]int {
+	counts := make(map[types.AccountStatus]int)
+	o.countAccountsByStatus(counts)
+	return counts
 }
 
-// fillAccountsRecursive fills the OU tree with the accounts in the OUs.
-func (parent *OU) fillAccountsRecursive(ctx context.Context, api *organizations.Client) (*OU, error) {
-	// Get the accounts for the parent OU.
-	accounts, err := getAccountsFromOU(ctx, api, parent.Id, parent.Name)
-	if err != nil {
-		return nil, err
+// countAccountsByStatus adds the accounts in the OU and every OU below it to
+// the counts of each status.
+func (o *OU) countAccountsByStatus(counts map[types.AccountStatus]int) {
+	for _, account := range o.Accounts {
+		counts[account.Status]++
 	}
-	parent.Accounts = accounts
-
-	// Recursively fill the tree with the accounts.
-	for i := range parent.Children {
-		ou, err := parent.Children[i].fillAccountsRecursive(ctx, api)
-		if err != nil {
-			return nil, err
-		}
-		parent.Children[i] = ou
+	for _, child := range o.Children {
+		child.countAccountsByStatus(counts)
 	}
+}
 
-	return parent, nil
+// ToJSON returns a JSON representation of the OU.
+func (o *OU) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(o, "", "  ")
 }
 
 // removeSuspendedAccounts removes all suspended accounts from the OU tree.
 func (parent *OU) RemoveSuspendedAccounts() *OU {
-	accounts := make([]types.Account, 0)
+	accounts := make([]Account, 0)
 	for _, account := range parent.Accounts {
 		if account.Status != types.AccountStatusSuspended {
 			accounts = append(accounts, account)
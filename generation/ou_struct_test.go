@@ -1,11 +1,9 @@
 package generation
 
 import (
-	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
 	"github.com/stretchr/testify/require"
 )
@@ -83,57 +81,6 @@ func TestOuGetters(t *testing.T) {
 	require.Equal(t, 0, len(ou.GetAccounts()), "OU accounts getter returned incorrect value")
 }
 
-// TestOuFillOuTree tests the fillOuTree method of the OU struct.
-func TestOuFillOuTree(t *testing.T) {
-	// Create a mock API.
-	mockClient := ListOrganizationalUnitsForParentMock{
-		ListOrganizationalUnitsForParentFunc: func(
-			ctx context.Context,
-			params *organizations.ListOrganizationalUnitsForParentInput,
-			optFns ...func(*organizations.Options),
-		) (
-			*organizations.ListOrganizationalUnitsForParentOutput,
-			error,
-		) {
-			// Check that the correct parent ID was passed in
-			// If not, return an empty list of OUs.
-			if *params.ParentId != "ou-1234" {
-				return &organizations.ListOrganizationalUnitsForParentOutput{
-					OrganizationalUnits: []types.OrganizationalUnit{},
-				}, nil
-			}
-
-			// Create the mock output
-			output := &organizations.ListOrganizationalUnitsForParentOutput{
-				OrganizationalUnits: []types.OrganizationalUnit{
-					{
-						Id:   aws.String("ou-5678"),
-						Name: aws.String("TestChildOU"),
-					},
-				},
-			}
-
-			return output, nil
-		},
-	}
-
-	// Create an OU struct.
-	ou := &OU{
-		Id:   "ou-1234",
-		Name: "TestOU",
-	}
-
-	// Fill the OU tree.
-	ctx := context.Background()
-	err := ou.fillOuTree(ctx, &mockClient)
-	require.NoError(t, err, "fillOuTree returned an error")
-
-	// Check that the child OU was added correctly.
-	require.Equal(t, 1, len(ou.Children), "OU children was not set correctly")
-	require.Equal(t, "ou-5678", ou.Children[0].Id, "OU children was not set correctly")
-	require.Equal(t, "TestChildOU", ou.Children[0].Name, "OU children was not set correctly")
-}
-
 // TestRemoveSuspendedAccounts tests the removeSuspendedAccounts method of the OU struct.
 func TestRemoveSuspendedAccounts(t *testing.T) {
 	// Create an OU struct.
@@ -143,14 +90,18 @@ func TestRemoveSuspendedAccounts(t *testing.T) {
 	}
 
 	// Create a list of accounts.
-	accounts := []types.Account{
+	accounts := []Account{
 		{
-			Id:     aws.String("123456789"),
-			Status: types.AccountStatusActive,
+			Account: types.Account{
+				Id:     aws.String("123456789"),
+				Status: types.AccountStatusActive,
+			},
 		},
 		{
-			Id:     aws.String("987654321"),
-			Status: types.AccountStatusSuspended,
+			Account: types.Account{
+				Id:     aws.String("987654321"),
+				Status: types.AccountStatusSuspended,
+			},
 		},
 	}
 
@@ -164,3 +115,40 @@ func TestRemoveSuspendedAccounts(t *testing.T) {
 	require.Len(t, ou.Accounts, 1, "removeSuspendedAccounts did not remove the correct accounts")
 	require.Equal(t, "123456789", *ou.Accounts[0].Id, "removeSuspendedAccounts did not remove the correct accounts")
 }
+
+// TestOuTotals tests that the accounts and OUs below an OU are counted.
+func TestOuTotals(t *testing.T) {
+	account := func(status types.AccountStatus) Account {
+		return Account{Account: types.Account{Status: status}}
+	}
+	ou := &OU{
+		Id:       "r-1234",
+		Accounts: []Account{account(types.AccountStatusActive)},
+		Children: []*OU{
+			{
+				Id: "ou-1",
+				Accounts: []Account{
+					account(types.AccountStatusActive),
+					account(types.AccountStatusSuspended),
+				},
+				Children: []*OU{
+					{Id: "ou-1-1", Accounts: []Account{account(types.AccountStatusPendingClosure)}},
+				},
+			},
+			{Id: "ou-2"},
+		},
+	}
+
+	require.Equal(t, 4, ou.TotalAccounts())
+	require.Equal(t, 3, ou.TotalOUs())
+	require.Equal(t, map[types.AccountStatus]int{
+		types.AccountStatusActive:         2,
+		types.AccountStatusSuspended:      1,
+		types.AccountStatusPendingClosure: 1,
+	}, ou.AccountsByStatus())
+
+	// An OU with nothing below it has no totals.
+	require.Equal(t, 0, ou.Children[1].TotalAccounts())
+	require.Equal(t, 0, ou.Children[1].TotalOUs())
+	require.Empty(t, ou.Children[1].AccountsByStatus())
+}
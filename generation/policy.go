@@ -0,0 +1,190 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// PolicyTypeChatbotPolicy is the policy type for chatbot policies, it is not
+// yet defined by the version of the AWS SDK in use.
+const PolicyTypeChatbotPolicy types.PolicyType = "CHATBOT_POLICY"
+
+// AllPolicyTypes is every policy type that can be requested, in the order they
+// are displayed.
+var AllPolicyTypes = []types.PolicyType{
+	types.PolicyTypeServiceControlPolicy,
+	types.PolicyTypeTagPolicy,
+	types.PolicyTypeBackupPolicy,
+	types.PolicyTypeAiservicesOptOutPolicy,
+	PolicyTypeChatbotPolicy,
+}
+
+// ParsePolicyTypes parses a comma separated list of policy types, such as
+// "SERVICE_CONTROL_POLICY,TAG_POLICY", into a slice. The names are not case
+// sensitive and "ALL" can be used to select every policy type.
+func ParsePolicyTypes(list string) ([]types.PolicyType, error) {
+	policyTypes := make([]types.PolicyType, 0)
+	seen := map[types.PolicyType]bool{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == "ALL" {
+			return AllPolicyTypes, nil
+		}
+
+		policyType := types.PolicyType(name)
+		if !isKnownPolicyType(policyType) {
+			return nil, fmt.Errorf("unknown policy type %q", name)
+		}
+		if !seen[policyType] {
+			seen[policyType] = true
+			policyTypes = append(policyTypes, policyType)
+		}
+	}
+	return policyTypes, nil
+}
+
+// isKnownPolicyType reports whether the policy type is one of AllPolicyTypes.
+func isKnownPolicyType(policyType types.PolicyType) bool {
+	for _, known := range AllPolicyTypes {
+		if policyType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// --- PolicyTypeSummary -------------------------------------------------------
+// PolicyTypeSummary is a struct that represents the status of a policy type on
+// the root of the organization.
+type PolicyTypeSummary struct {
+	Type   types.PolicyType       `json:"type"`
+	Status types.PolicyTypeStatus `json:"status"`
+}
+
+// --- Policies ----------------------------------------------------------------
+// Policies is the policies attached directly to a target, keyed by their type.
+type Policies map[types.PolicyType][]Policy
+
+// Types returns the policy types that have at least one policy, in the order
+// of AllPolicyTypes followed by any other types in alphabetical order.
+func (p Policies) Types() []types.PolicyType {
+	policyTypes := make([]types.PolicyType, 0, len(p))
+	for _, policyType := range AllPolicyTypes {
+		if len(p[policyType]) > 0 {
+			policyTypes = append(policyTypes, policyType)
+		}
+	}
+	others := make([]string, 0)
+	for policyType, policies := range p {
+		if len(policies) > 0 && !isKnownPolicyType(policyType) {
+			others = append(others, string(policyType))
+		}
+	}
+	sort.Strings(others)
+	for _, policyType := range others {
+		policyTypes = append(policyTypes, types.PolicyType(policyType))
+	}
+	return policyTypes
+}
+
+// --- Policy ------------------------------------------------------------------
+// Policy is a struct that represents an Organizations policy attached directly
+// to the root, an OU or an account.
+type Policy struct {
+	Id          string           `json:"id"`
+	Name        string           `json:"name"`
+	Type        types.PolicyType `json:"type"`
+	Description string           `json:"description,omitempty"`
+	AwsManaged  bool             `json:"awsManaged"`
+	Content     string           `json:"content"`
+}
+
+// getPoliciesForTarget gets the summaries of all the policies of the given
+// type attached directly to the target, following the NextToken of each
+// response until every page has been read.
+func getPoliciesForTarget(ctx context.Context, api ListPoliciesForTarget, retry RetryPolicy, targetId string, policyType types.PolicyType) ([]types.PolicySummary, error) {
+	policies := make([]types.PolicySummary, 0)
+	var nextToken *string
+	for {
+		page, err := withRetry(ctx, retry, func() (*organizations.ListPoliciesForTargetOutput, error) {
+			return api.ListPoliciesForTarget(ctx, &organizations.ListPoliciesForTargetInput{
+				TargetId:  &targetId,
+				Filter:    policyType,
+				NextToken: nextToken,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s policies for target %s: %w", policyType, targetId, err)
+		}
+		policies = append(policies, page.Policies...)
+
+		// Stop once the API reports there are no more pages.
+		if page.NextToken == nil || *page.NextToken == "" {
+			return policies, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// getPolicyContent gets the policy document of the policy with the given ID.
+func getPolicyContent(ctx context.Context, api DescribePolicy, retry RetryPolicy, policyId string) (string, error) {
+	output, err := withRetry(ctx, retry, func() (*organizations.DescribePolicyOutput, error) {
+		return api.DescribePolicy(ctx, &organizations.DescribePolicyInput{
+			PolicyId: &policyId,
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe policy %s: %w", policyId, err)
+	}
+	if output.Policy == nil {
+		return "", nil
+	}
+	return aws.ToString(output.Policy.Content), nil
+}
+
+// --- policyCache -------------------------------------------------------------
+// policyCache stores the content of each policy so that a policy attached to
+// many targets is only described once, even when the targets are crawled at
+// the same time.
+type policyCache struct {
+	mu      sync.Mutex
+	entries map[string]*policyCacheEntry
+}
+
+// policyCacheEntry holds the result of describing a single policy, once is used
+// to make sure the policy is only described by the first caller.
+type policyCacheEntry struct {
+	once    sync.Once
+	content string
+	err     error
+}
+
+// get returns the content of the policy with the given ID, calling fetch to
+// get it the first time the policy is requested.
+func (c *policyCache) get(policyId string, fetch func() (string, error)) (string, error) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*policyCacheEntry{}
+	}
+	entry, ok := c.entries[policyId]
+	if !ok {
+		entry = &policyCacheEntry{}
+		c.entries[policyId] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.content, entry.err = fetch()
+	})
+	return entry.content, entry.err
+}
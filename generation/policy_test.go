@@ -0,0 +1,259 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testAttachments is the policies attached to each target in the tests below,
+// keyed by the target ID.
+var testAttachments = map[string][]string{
+	"r-1234":     {"p-full"},
+	"ou-1":       {"p-full", "p-deny", "p-tag-cost"},
+	"acc-ou-1-2": {"p-deny"},
+}
+
+// newTestPolicyMocks creates the ListPoliciesForTarget and DescribePolicy mocks
+// for the testAttachments, counting the number of times each policy is
+// described.
+func newTestPolicyMocks(describes *int32) (*ListPoliciesForTargetMock, *DescribePolicyMock) {
+	list := &ListPoliciesForTargetMock{
+		ListPoliciesForTargetFunc: func(
+			ctx context.Context,
+			params *organizations.ListPoliciesForTargetInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListPoliciesForTargetOutput,
+			error,
+		) {
+			output := &organizations.ListPoliciesForTargetOutput{}
+			for _, id := range testAttachments[*params.TargetId] {
+				// Tag policies have IDs starting "p-tag".
+				policyType := types.PolicyTypeServiceControlPolicy
+				if strings.HasPrefix(id, "p-tag") {
+					policyType = types.PolicyTypeTagPolicy
+				}
+				if policyType != params.Filter {
+					continue
+				}
+				output.Policies = append(output.Policies, types.PolicySummary{
+					Id:         aws.String(id),
+					Name:       aws.String("Name " + id),
+					Type:       policyType,
+					AwsManaged: id == "p-full",
+				})
+			}
+			return output, nil
+		},
+	}
+	describe := &DescribePolicyMock{
+		DescribePolicyFunc: func(
+			ctx context.Context,
+			params *organizations.DescribePolicyInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.DescribePolicyOutput,
+			error,
+		) {
+			atomic.AddInt32(describes, 1)
+			return &organizations.DescribePolicyOutput{
+				Policy: &types.Policy{
+					Content: aws.String(`{"Statement":"` + *params.PolicyId + `"}`),
+				},
+			}, nil
+		},
+	}
+	return list, describe
+}
+
+// TestGetPoliciesForTarget tests the getPoliciesForTarget function follows the
+// NextToken of each response and filters by the policy type.
+func TestGetPoliciesForTarget(t *testing.T) {
+	// Create the mock
+	mockClient := ListPoliciesForTargetMock{
+		ListPoliciesForTargetFunc: func(
+			ctx context.Context,
+			params *organizations.ListPoliciesForTargetInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListPoliciesForTargetOutput,
+			error,
+		) {
+			// Check that the correct target and filter were passed in
+			if *params.TargetId != "ou-1234" {
+				return nil, fmt.Errorf("expected target ID to be ou-1234, got %s", *params.TargetId)
+			}
+			if params.Filter != types.PolicyTypeServiceControlPolicy {
+				return nil, fmt.Errorf("unexpected filter %s", params.Filter)
+			}
+
+			if params.NextToken == nil {
+				return &organizations.ListPoliciesForTargetOutput{
+					Policies:  []types.PolicySummary{{Id: aws.String("p-1")}},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &organizations.ListPoliciesForTargetOutput{
+				Policies: []types.PolicySummary{{Id: aws.String("p-2")}},
+			}, nil
+		},
+	}
+
+	// Call the function
+	ctx := context.Background()
+	policies, err := getPoliciesForTarget(ctx, &mockClient, testRetryPolicy, "ou-1234", types.PolicyTypeServiceControlPolicy)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	require.Equal(t, "p-1", *policies[0].Id)
+	require.Equal(t, "p-2", *policies[1].Id)
+
+	// Check that errors are returned
+	_, err = getPoliciesForTarget(ctx, &mockClient, testRetryPolicy, "ou-4321", types.PolicyTypeServiceControlPolicy)
+	require.Error(t, err)
+}
+
+// TestGetPolicyContent tests the getPolicyContent function returns the policy
+// document from DescribePolicy.
+func TestGetPolicyContent(t *testing.T) {
+	var describes int32
+	_, mockClient := newTestPolicyMocks(&describes)
+
+	ctx := context.Background()
+	content, err := getPolicyContent(ctx, mockClient, testRetryPolicy, "p-1")
+	require.NoError(t, err)
+	require.Equal(t, `{"Statement":"p-1"}`, content)
+	require.Equal(t, int32(1), describes)
+}
+
+// TestPolicyCache tests that the policyCache only fetches each policy once.
+func TestPolicyCache(t *testing.T) {
+	cache := policyCache{}
+	calls := 0
+	fetch := func() (string, error) {
+		calls++
+		return "content", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		content, err := cache.get("p-1", fetch)
+		require.NoError(t, err)
+		require.Equal(t, "content", content)
+	}
+	require.Equal(t, 1, calls, "policy was fetched more than once")
+
+	_, err := cache.get("p-2", func() (string, error) { return "", fmt.Errorf("Testing Error") })
+	require.Error(t, err)
+}
+
+// TestCrawlerFillTreePolicies tests that the crawler attaches the policies to
+// the root, the OUs and the accounts, describing each policy once.
+func TestCrawlerFillTreePolicies(t *testing.T) {
+	var inFlight, maxInFlight, describes int32
+	api := newTestOrganizationsMock(&inFlight, &maxInFlight)
+	list, describe := newTestPolicyMocks(&describes)
+	api.ListPoliciesForTargetMock = *list
+	api.DescribePolicyMock = *describe
+
+	c := newCrawler(api, Options{
+		Retry:       testRetryPolicy,
+		Parallelism: 4,
+		PolicyTypes: []types.PolicyType{types.PolicyTypeServiceControlPolicy, types.PolicyTypeTagPolicy},
+	})
+	tree := &OU{Id: "r-1234", Name: "Root"}
+	err := c.fillTree(context.Background(), tree)
+	require.NoError(t, err, "fillTree returned an error")
+
+	// The root and OU policies are attached to the right targets by type.
+	scps := tree.Policies[types.PolicyTypeServiceControlPolicy]
+	require.Len(t, tree.Policies, 1)
+	require.Len(t, scps, 1)
+	require.Equal(t, "p-full", scps[0].Id)
+	require.Equal(t, "Name p-full", scps[0].Name)
+	require.Equal(t, types.PolicyTypeServiceControlPolicy, scps[0].Type)
+	require.True(t, scps[0].AwsManaged)
+	require.Equal(t, `{"Statement":"p-full"}`, scps[0].Content)
+	require.Len(t, tree.Children[0].Policies[types.PolicyTypeServiceControlPolicy], 2)
+	require.Equal(t, "p-deny", tree.Children[0].Policies[types.PolicyTypeServiceControlPolicy][1].Id)
+	require.Len(t, tree.Children[0].Policies[types.PolicyTypeTagPolicy], 1)
+	require.Equal(t, "p-tag-cost", tree.Children[0].Policies[types.PolicyTypeTagPolicy][0].Id)
+	require.Len(t, tree.Children[1].Policies, 0)
+
+	// The account policies are attached to the accounts.
+	account := tree.Children[0].Children[1].Accounts[0]
+	require.Equal(t, "acc-ou-1-2", *account.Id)
+	require.Len(t, account.Policies[types.PolicyTypeServiceControlPolicy], 1)
+	require.Equal(t, `{"Statement":"p-deny"}`, account.Policies[types.PolicyTypeServiceControlPolicy][0].Content)
+
+	// Each policy is only described once.
+	require.Equal(t, int32(3), describes)
+}
+
+// TestCrawlerFillTreeNoPolicies tests that the crawler doesn't request any
+// policies unless asked to.
+func TestCrawlerFillTreeNoPolicies(t *testing.T) {
+	var inFlight, maxInFlight int32
+	c := newCrawler(newTestOrganizationsMock(&inFlight, &maxInFlight), Options{Retry: testRetryPolicy})
+	tree := &OU{Id: "r-1234", Name: "Root"}
+
+	// The policy mocks are nil so calling them would panic.
+	err := c.fillTree(context.Background(), tree)
+	require.NoError(t, err, "fillTree returned an error")
+	require.Nil(t, tree.Policies)
+	require.Nil(t, tree.Accounts[0].Policies)
+}
+
+// TestParsePolicyTypes tests the ParsePolicyTypes function.
+func TestParsePolicyTypes(t *testing.T) {
+	policyTypes, err := ParsePolicyTypes("")
+	require.NoError(t, err)
+	require.Empty(t, policyTypes)
+
+	policyTypes, err = ParsePolicyTypes(" tag_policy, SERVICE_CONTROL_POLICY,TAG_POLICY ")
+	require.NoError(t, err)
+	require.Equal(t, []types.PolicyType{types.PolicyTypeTagPolicy, types.PolicyTypeServiceControlPolicy}, policyTypes)
+
+	policyTypes, err = ParsePolicyTypes("all")
+	require.NoError(t, err)
+	require.Equal(t, AllPolicyTypes, policyTypes)
+
+	_, err = ParsePolicyTypes("SERVICE_CONTROL_POLICY,NOT_A_POLICY")
+	require.Error(t, err)
+}
+
+// TestPoliciesTypes tests the Types method of Policies returns the types in a
+// fixed order and skips types with no policies.
+func TestPoliciesTypes(t *testing.T) {
+	policies := Policies{
+		"ZZZ_POLICY":                           {{Id: "p-z"}},
+		types.PolicyTypeBackupPolicy:           {{Id: "p-backup"}},
+		types.PolicyTypeServiceControlPolicy:   {{Id: "p-scp"}},
+		types.PolicyTypeAiservicesOptOutPolicy: {},
+		"AAA_POLICY":                           {{Id: "p-a"}},
+	}
+	require.Equal(t, []types.PolicyType{
+		types.PolicyTypeServiceControlPolicy,
+		types.PolicyTypeBackupPolicy,
+		"AAA_POLICY",
+		"ZZZ_POLICY",
+	}, policies.Types())
+}
+
+// TestEnabledPolicyTypes tests that only the requested policy types enabled on
+// the root are kept.
+func TestEnabledPolicyTypes(t *testing.T) {
+	root := []PolicyTypeSummary{
+		{Type: types.PolicyTypeServiceControlPolicy, Status: types.PolicyTypeStatusEnabled},
+		{Type: types.PolicyTypeTagPolicy, Status: types.PolicyTypeStatusPendingEnable},
+		{Type: types.PolicyTypeBackupPolicy, Status: types.PolicyTypeStatusEnabled},
+	}
+	enabled := enabledPolicyTypes(AllPolicyTypes, root)
+	require.Equal(t, []types.PolicyType{types.PolicyTypeServiceControlPolicy, types.PolicyTypeBackupPolicy}, enabled)
+}
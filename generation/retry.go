@@ -0,0 +1,122 @@
+package generation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+// --- RetryPolicy -------------------------------------------------------------
+// RetryPolicy controls how calls to the Organizations API are retried when AWS
+// throttles them. The delay before each retry grows exponentially from
+// BaseDelay up to MaxDelay, with full jitter applied so that concurrent
+// requests don't all retry at the same moment.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times a call is made before giving up.
+	MaxAttempts int
+	// BaseDelay is the upper bound of the delay before the first retry.
+	BaseDelay time.Duration
+	// MaxDelay is the largest delay allowed between two attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy used when none is given in the Options.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    20 * time.Second,
+}
+
+// throttlingErrorCodes are the API error codes returned by AWS when a request
+// has been rate limited.
+var throttlingErrorCodes = map[string]bool{
+	"TooManyRequestsException": true,
+	"ThrottlingException":      true,
+}
+
+// withDefaults fills in any unset fields of the policy from the
+// DefaultRetryPolicy.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p
+}
+
+// backoff returns how long to wait before the given retry, where 0 is the
+// first retry. The delay is picked at random between zero and the exponential
+// backoff for that retry, capped at MaxDelay.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.MaxDelay
+	if retry < 32 {
+		if d := p.BaseDelay << uint(retry); d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// isThrottlingError reports whether the error, or any error it wraps, is an
+// AWS API error saying the request was throttled.
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return throttlingErrorCodes[apiErr.ErrorCode()]
+	}
+	return false
+}
+
+// WithRetry calls fn, retrying it with the policy while AWS throttles it in the
+// same way as the calls made to generate the structure. It is used by the other
+// packages that call the Organizations API.
+func WithRetry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	return withRetry(ctx, policy, fn)
+}
+
+// withRetry calls fn until it succeeds, returns an error that isn't caused by
+// throttling, or the policy runs out of attempts. It stops early if the
+// context is cancelled or the next delay would pass the context's deadline.
+func withRetry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	policy = policy.withDefaults()
+
+	var zero T
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil {
+			return result, nil
+		}
+		if !isThrottlingError(err) {
+			return zero, err
+		}
+		if attempt >= policy.MaxAttempts {
+			return zero, fmt.Errorf("throttled after %d attempts: %w", attempt, err)
+		}
+
+		// Don't sleep past the deadline, there would be no time left to retry.
+		delay := policy.backoff(attempt - 1)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return zero, fmt.Errorf("throttled and the context deadline leaves no time to retry: %w", err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
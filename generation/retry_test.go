@@ -0,0 +1,126 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+)
+
+// testRetryPolicy is a RetryPolicy with very short delays so the tests that
+// hit rate limits run quickly.
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    2 * time.Millisecond,
+}
+
+// TestIsThrottlingError tests that throttling errors are detected by their API
+// error code, including when wrapped by the SDK's own retryer.
+func TestIsThrottlingError(t *testing.T) {
+	require.True(t, isThrottlingError(&smithy.GenericAPIError{Code: "TooManyRequestsException"}))
+	require.True(t, isThrottlingError(&smithy.GenericAPIError{Code: "ThrottlingException"}))
+	require.True(t, isThrottlingError(&retry.MaxAttemptsError{
+		Attempt: 3,
+		Err:     &smithy.GenericAPIError{Code: "TooManyRequestsException"},
+	}))
+	require.True(t, isThrottlingError(fmt.Errorf("wrapped: %w", &smithy.GenericAPIError{Code: "ThrottlingException"})))
+
+	require.False(t, isThrottlingError(&smithy.GenericAPIError{Code: "AccessDeniedException"}))
+	require.False(t, isThrottlingError(fmt.Errorf("exceeded maximum number of attempts")))
+	require.False(t, isThrottlingError(nil))
+}
+
+// TestRetryPolicyWithDefaults tests that unset fields are filled in from the
+// DefaultRetryPolicy.
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	require.Equal(t, DefaultRetryPolicy, RetryPolicy{}.withDefaults())
+
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute}.withDefaults()
+	require.Equal(t, 2, policy.MaxAttempts)
+	require.Equal(t, time.Minute, policy.BaseDelay)
+	require.Equal(t, time.Minute, policy.MaxDelay, "MaxDelay should never be below BaseDelay")
+}
+
+// TestRetryPolicyBackoff tests that the backoff grows exponentially and never
+// goes above the maximum delay.
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		require.LessOrEqual(t, policy.backoff(0), 10*time.Millisecond)
+		require.LessOrEqual(t, policy.backoff(1), 20*time.Millisecond)
+		require.LessOrEqual(t, policy.backoff(5), 50*time.Millisecond)
+		require.LessOrEqual(t, policy.backoff(100), 50*time.Millisecond)
+		require.GreaterOrEqual(t, policy.backoff(3), time.Duration(0))
+	}
+}
+
+// TestWithRetry tests that throttled calls are retried until they succeed.
+func TestWithRetry(t *testing.T) {
+	calls := 0
+	result, err := withRetry(context.Background(), testRetryPolicy, func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", &smithy.GenericAPIError{Code: "ThrottlingException"}
+		}
+		return "done", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "done", result)
+	require.Equal(t, 3, calls)
+}
+
+// TestWithRetryOtherError tests that errors other than throttling are returned
+// straight away.
+func TestWithRetryOtherError(t *testing.T) {
+	calls := 0
+	_, err := withRetry(context.Background(), testRetryPolicy, func() (string, error) {
+		calls++
+		return "", fmt.Errorf("Testing Error")
+	})
+	require.EqualError(t, err, "Testing Error")
+	require.Equal(t, 1, calls)
+}
+
+// TestWithRetryExhausted tests that the last throttling error is returned once
+// the policy runs out of attempts.
+func TestWithRetryExhausted(t *testing.T) {
+	calls := 0
+	_, err := withRetry(context.Background(), testRetryPolicy, func() (string, error) {
+		calls++
+		return "", &smithy.GenericAPIError{Code: "TooManyRequestsException"}
+	})
+	require.True(t, isThrottlingError(err), "the throttling error should be wrapped")
+	require.Equal(t, testRetryPolicy.MaxAttempts, calls)
+}
+
+// TestWithRetryContext tests that retries stop when the context is cancelled
+// or its deadline would pass before the next attempt.
+func TestWithRetryContext(t *testing.T) {
+	slowPolicy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+
+	// A cancelled context stops the wait before the next attempt.
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := withRetry(ctx, slowPolicy, func() (string, error) {
+		calls++
+		cancel()
+		return "", &smithy.GenericAPIError{Code: "ThrottlingException"}
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+
+	// A deadline shorter than the backoff is not slept through.
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = withRetry(ctx, slowPolicy, func() (string, error) {
+		return "", &smithy.GenericAPIError{Code: "ThrottlingException"}
+	})
+	require.Error(t, err)
+	require.Less(t, time.Since(start), time.Second)
+}
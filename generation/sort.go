@@ -0,0 +1,83 @@
+package generation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// SortOrder is the order to sort the OUs and accounts in a tree by.
+type SortOrder string
+
+const (
+	// SortByName sorts the OUs and accounts by their names.
+	SortByName SortOrder = "name"
+	// SortById sorts the OUs and accounts by their IDs.
+	SortById SortOrder = "id"
+	// SortByAccounts sorts the OUs by the total number of accounts in them and
+	// the OUs below them, most first, then by their names. The accounts are
+	// left in the order they were returned.
+	SortByAccounts SortOrder = "accounts"
+)
+
+// ParseSortOrder returns the sort order with the given name. An empty name
+// returns an empty sort order, which leaves the tree in the order the API
+// returned it.
+func ParseSortOrder(name string) (SortOrder, error) {
+	switch SortOrder(name) {
+	case "", SortByName, SortById, SortByAccounts:
+		return SortOrder(name), nil
+	}
+	return "", fmt.Errorf("unknown sort order %s, expected name, id or accounts", name)
+}
+
+// Sort sorts the children and accounts of every OU in the tree in the given
+// order, it does nothing if the order is empty.
+func (parent *OU) Sort(order SortOrder) *OU {
+	switch order {
+	case SortByName:
+		sort.SliceStable(parent.Children, func(i, j int) bool {
+			return parent.Children[i].Name < parent.Children[j].Name
+		})
+		sort.SliceStable(parent.Accounts, func(i, j int) bool {
+			return aws.ToString(parent.Accounts[i].Name) < aws.ToString(parent.Accounts[j].Name)
+		})
+	case SortById:
+		sort.SliceStable(parent.Children, func(i, j int) bool {
+			return parent.Children[i].Id < parent.Children[j].Id
+		})
+		sort.SliceStable(parent.Accounts, func(i, j int) bool {
+			return aws.ToString(parent.Accounts[i].Id) < aws.ToString(parent.Accounts[j].Id)
+		})
+	case SortByAccounts:
+		totals := make(map[*OU]int, len(parent.Children))
+		for _, child := range parent.Children {
+			totals[child] = child.TotalAccounts()
+		}
+		sort.SliceStable(parent.Children, func(i, j int) bool {
+			a, b := parent.Children[i], parent.Children[j]
+			if totals[a] != totals[b] {
+				return totals[a] > totals[b]
+			}
+			return a.Name < b.Name
+		})
+	default:
+		return parent
+	}
+
+	for i := range parent.Children {
+		parent.Children[i] = parent.Children[i].Sort(order)
+	}
+	return parent
+}
+
+// CollapsedSummary describes what is below the OU when its child OUs are
+// hidden by a depth limit, in the form "… 3 more OUs, 12 accounts". It returns
+// an empty string if the OU has no children.
+func (o *OU) CollapsedSummary() string {
+	if len(o.Children) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("… %d more OUs, %d accounts", o.TotalOUs(), o.TotalAccounts()-len(o.Accounts))
+}
@@ -0,0 +1,96 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSortOU creates a tree with the OUs and accounts in no particular
+// order.
+func newTestSortOU() *OU {
+	account := func(id, name string) Account {
+		return Account{Account: types.Account{Id: aws.String(id), Name: aws.String(name)}}
+	}
+	return &OU{
+		Id:       "r-1234",
+		Name:     "Root",
+		Accounts: []Account{account("3", "b"), account("1", "c"), account("2", "a")},
+		Children: []*OU{
+			{Id: "ou-3", Name: "Beta", Accounts: []Account{account("4", "d")}},
+			{
+				Id:   "ou-1",
+				Name: "Gamma",
+				Children: []*OU{
+					{Id: "ou-5", Name: "Zeta", Accounts: []Account{account("5", "e"), account("6", "f")}},
+					{Id: "ou-4", Name: "Eta"},
+				},
+			},
+			{Id: "ou-2", Name: "Alpha", Accounts: []Account{account("7", "g")}},
+		},
+	}
+}
+
+// names returns the names of the child OUs.
+func names(ous []*OU) []string {
+	result := make([]string, len(ous))
+	for i, ou := range ous {
+		result[i] = ou.Name
+	}
+	return result
+}
+
+// accountIds returns the IDs of the accounts.
+func accountIds(accounts []Account) []string {
+	result := make([]string, len(accounts))
+	for i, account := range accounts {
+		result[i] = aws.ToString(account.Id)
+	}
+	return result
+}
+
+// TestParseSortOrder tests that the sort orders are parsed by their names.
+func TestParseSortOrder(t *testing.T) {
+	for _, name := range []string{"", "name", "id", "accounts"} {
+		order, err := ParseSortOrder(name)
+		require.NoError(t, err)
+		require.Equal(t, SortOrder(name), order)
+	}
+	_, err := ParseSortOrder("size")
+	require.Error(t, err)
+}
+
+// TestSort tests that every level of the tree is sorted in each order.
+func TestSort(t *testing.T) {
+	ou := newTestSortOU().Sort(SortByName)
+	require.Equal(t, []string{"Alpha", "Beta", "Gamma"}, names(ou.Children))
+	require.Equal(t, []string{"Eta", "Zeta"}, names(ou.Children[2].Children))
+	require.Equal(t, []string{"2", "3", "1"}, accountIds(ou.Accounts))
+
+	ou = newTestSortOU().Sort(SortById)
+	require.Equal(t, []string{"Gamma", "Alpha", "Beta"}, names(ou.Children))
+	require.Equal(t, []string{"Eta", "Zeta"}, names(ou.Children[0].Children))
+	require.Equal(t, []string{"1", "2", "3"}, accountIds(ou.Accounts))
+
+	// Ties in the number of accounts are sorted by name, and the accounts keep
+	// their order.
+	ou = newTestSortOU().Sort(SortByAccounts)
+	require.Equal(t, []string{"Gamma", "Alpha", "Beta"}, names(ou.Children))
+	require.Equal(t, []string{"Zeta", "Eta"}, names(ou.Children[0].Children))
+	require.Equal(t, []string{"3", "1", "2"}, accountIds(ou.Accounts))
+
+	// Without an order the tree is left as it is.
+	ou = newTestSortOU().Sort("")
+	require.Equal(t, []string{"Beta", "Gamma", "Alpha"}, names(ou.Children))
+}
+
+// TestCollapsedSummary tests that the OUs and accounts below an OU are
+// summarised.
+func TestCollapsedSummary(t *testing.T) {
+	ou := newTestSortOU()
+	require.Equal(t, "… 5 more OUs, 4 accounts", ou.CollapsedSummary())
+	require.Equal(t, "… 2 more OUs, 2 accounts", ou.Children[1].CollapsedSummary())
+	require.Equal(t, "", ou.Children[0].CollapsedSummary())
+}
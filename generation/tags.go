@@ -0,0 +1,37 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+)
+
+// getTagsForResource gets all the tags on the given root, OU or account as a
+// map of key to value, following the NextToken of each response until every
+// page has been read.
+func getTagsForResource(ctx context.Context, api ListTagsForResource, retry RetryPolicy, resourceId string) (map[string]string, error) {
+	tags := map[string]string{}
+	var nextToken *string
+	for {
+		page, err := withRetry(ctx, retry, func() (*organizations.ListTagsForResourceOutput, error) {
+			return api.ListTagsForResource(ctx, &organizations.ListTagsForResourceInput{
+				ResourceId: &resourceId,
+				NextToken:  nextToken,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags for %s: %w", resourceId, err)
+		}
+		for _, tag := range page.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+
+		// Stop once the API reports there are no more pages.
+		if page.NextToken == nil || *page.NextToken == "" {
+			return tags, nil
+		}
+		nextToken = page.NextToken
+	}
+}
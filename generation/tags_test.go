@@ -0,0 +1,82 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTagsMock creates a ListTagsForResourceMock that returns an owner tag
+// for every resource, split over two pages.
+func newTestTagsMock() *ListTagsForResourceMock {
+	return &ListTagsForResourceMock{
+		ListTagsForResourceFunc: func(
+			ctx context.Context,
+			params *organizations.ListTagsForResourceInput,
+			optFns ...func(*organizations.Options),
+		) (
+			*organizations.ListTagsForResourceOutput,
+			error,
+		) {
+			if *params.ResourceId == "fail" {
+				return nil, fmt.Errorf("Testing Error")
+			}
+			if params.NextToken == nil {
+				return &organizations.ListTagsForResourceOutput{
+					Tags: []types.Tag{
+						{Key: aws.String("owner"), Value: aws.String("owner-of-" + *params.ResourceId)},
+					},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &organizations.ListTagsForResourceOutput{
+				Tags: []types.Tag{
+					{Key: aws.String("env"), Value: aws.String("prod")},
+				},
+			}, nil
+		},
+	}
+}
+
+// TestGetTagsForResource tests the getTagsForResource function reads every page
+// of tags into a map.
+func TestGetTagsForResource(t *testing.T) {
+	ctx := context.Background()
+	tags, err := getTagsForResource(ctx, newTestTagsMock(), testRetryPolicy, "ou-1234")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"owner": "owner-of-ou-1234", "env": "prod"}, tags)
+
+	_, err = getTagsForResource(ctx, newTestTagsMock(), testRetryPolicy, "fail")
+	require.Error(t, err)
+}
+
+// TestCrawlerFillTreeTags tests that the crawler tags the root, the OUs and the
+// accounts when asked to.
+func TestCrawlerFillTreeTags(t *testing.T) {
+	var inFlight, maxInFlight int32
+	api := newTestOrganizationsMock(&inFlight, &maxInFlight)
+	api.ListTagsForResourceMock = *newTestTagsMock()
+
+	c := newCrawler(api, Options{Retry: testRetryPolicy, IncludeTags: true})
+	tree := &OU{Id: "r-1234", Name: "Root"}
+	err := c.fillTree(context.Background(), tree)
+	require.NoError(t, err, "fillTree returned an error")
+
+	require.Equal(t, "owner-of-r-1234", tree.Tags["owner"])
+	require.Equal(t, "owner-of-ou-3-1", tree.Children[2].Children[0].Tags["owner"])
+	require.Equal(t, "owner-of-acc-ou-1-2", tree.Children[0].Children[1].Accounts[0].Tags["owner"])
+	require.Equal(t, "prod", tree.Children[0].Children[1].Accounts[0].Tags["env"])
+
+	// Without IncludeTags no tags are requested.
+	c = newCrawler(newTestOrganizationsMock(&inFlight, &maxInFlight), Options{Retry: testRetryPolicy})
+	tree = &OU{Id: "r-1234", Name: "Root"}
+	err = c.fillTree(context.Background(), tree)
+	require.NoError(t, err, "fillTree returned an error")
+	require.Nil(t, tree.Tags)
+	require.Nil(t, tree.Accounts[0].Tags)
+}
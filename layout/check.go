@@ -0,0 +1,141 @@
+package layout
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// FindingType is the kind of drift found between the layout and the
+// organization.
+type FindingType string
+
+const (
+	MissingOU        FindingType = "MISSING_OU"
+	UnexpectedOU     FindingType = "UNEXPECTED_OU"
+	MisplacedAccount FindingType = "MISPLACED_ACCOUNT"
+	MissingAccount   FindingType = "MISSING_ACCOUNT"
+)
+
+// --- Finding -----------------------------------------------------------------
+// Finding is a struct that represents a single difference between the desired
+// layout and the organization.
+type Finding struct {
+	Type FindingType `json:"type"`
+	// Path is the path of names from the root to the OU, or to the OU the
+	// account should be in.
+	Path string `json:"path"`
+	// Id is the ID of the OU or account, it is empty for missing OUs.
+	Id string `json:"id,omitempty"`
+	// Name is the name of the account, if it is in the organization.
+	Name string `json:"name,omitempty"`
+	// Actual is the path of the OU a misplaced account is in.
+	Actual string `json:"actual,omitempty"`
+}
+
+// String returns a human readable description of the finding.
+func (f Finding) String() string {
+	switch f.Type {
+	case MissingOU:
+		return fmt.Sprintf("missing OU %s", f.Path)
+	case UnexpectedOU:
+		return fmt.Sprintf("unexpected OU %s (%s)", f.Path, f.Id)
+	case MisplacedAccount:
+		return fmt.Sprintf("misplaced account %s (%s) is in %s, expected %s", f.Name, f.Id, f.Actual, f.Path)
+	case MissingAccount:
+		return fmt.Sprintf("missing account %s, expected in %s", f.Id, f.Path)
+	}
+	return fmt.Sprintf("%s %s", f.Type, f.Path)
+}
+
+// --- Findings ----------------------------------------------------------------
+// Findings is the list of every difference between the desired layout and the
+// organization.
+type Findings []Finding
+
+// ToJSON returns a JSON representation of the findings.
+func (f Findings) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Report returns a human readable report of the findings, one per line.
+func (f Findings) Report() string {
+	if len(f) == 0 {
+		return "No drift from the layout\n"
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "%d difference(s) from the layout:\n", len(f))
+	for _, finding := range f {
+		fmt.Fprintf(&report, "  - %s\n", finding)
+	}
+	return report.String()
+}
+
+// Check compares the organization's tree with the desired layout and returns
+// every OU that is missing or unexpected, and every account listed in the
+// layout that isn't where it should be. The findings are in the order the OUs
+// appear in the layout, with unexpected OUs in the order they appear in the
+// tree.
+func Check(layout *Layout, root *generation.OU) Findings {
+	findings := make(Findings, 0)
+
+	// Index where each account actually is.
+	type location struct {
+		name string
+		path string
+	}
+	actual := make(map[string]location)
+	var index func(ou *generation.OU, path string)
+	index = func(ou *generation.OU, path string) {
+		for _, account := range ou.Accounts {
+			actual[aws.ToString(account.Id)] = location{name: aws.ToString(account.Name), path: path}
+		}
+		for _, child := range ou.Children {
+			index(child, path+"/"+child.Name)
+		}
+	}
+	index(root, layout.Root.Name)
+
+	// Walk the layout and the tree together, matching OUs by name.
+	var walk func(want OU, got *generation.OU, path string)
+	walk = func(want OU, got *generation.OU, path string) {
+		for _, id := range want.Accounts {
+			at, ok := actual[id]
+			if !ok {
+				findings = append(findings, Finding{Type: MissingAccount, Path: path, Id: id})
+			} else if at.path != path {
+				findings = append(findings, Finding{Type: MisplacedAccount, Path: path, Id: id, Name: at.name, Actual: at.path})
+			}
+		}
+
+		children := make(map[string]*generation.OU)
+		if got != nil {
+			for _, child := range got.Children {
+				children[child.Name] = child
+			}
+		}
+		wanted := make(map[string]bool)
+		for _, child := range want.OUs {
+			wanted[child.Name] = true
+			childPath := path + "/" + child.Name
+			if children[child.Name] == nil {
+				findings = append(findings, Finding{Type: MissingOU, Path: childPath})
+			}
+			walk(child, children[child.Name], childPath)
+		}
+
+		if got != nil {
+			for _, child := range got.Children {
+				if !wanted[child.Name] {
+					findings = append(findings, Finding{Type: UnexpectedOU, Path: path + "/" + child.Name, Id: child.Id})
+				}
+			}
+		}
+	}
+	walk(layout.Root, root, layout.Root.Name)
+	return findings
+}
@@ -0,0 +1,99 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTree creates the organization checked against the layouts in the
+// tests below.
+func newTestTree() *generation.OU {
+	return &generation.OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management")}},
+			{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Prod")}},
+		},
+		Children: []*generation.OU{
+			{
+				Id:   "ou-1",
+				Name: "Workloads",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("333333333333"), Name: aws.String("Dev")}},
+				},
+			},
+			{
+				Id:   "ou-2",
+				Name: "Legacy",
+			},
+		},
+	}
+}
+
+// TestCheck tests that every kind of drift from the layout is found.
+func TestCheck(t *testing.T) {
+	desired := &Layout{
+		Root: OU{
+			Name:     "Root",
+			Accounts: []string{"111111111111"},
+			OUs: []OU{
+				{
+					Name:     "Workloads",
+					Accounts: []string{"333333333333"},
+					OUs: []OU{
+						{Name: "Prod", Accounts: []string{"222222222222", "444444444444"}},
+					},
+				},
+			},
+		},
+	}
+
+	findings := Check(desired, newTestTree())
+	require.Equal(t, Findings{
+		{Type: MissingOU, Path: "Root/Workloads/Prod"},
+		{Type: MisplacedAccount, Path: "Root/Workloads/Prod", Id: "222222222222", Name: "Prod", Actual: "Root"},
+		{Type: MissingAccount, Path: "Root/Workloads/Prod", Id: "444444444444"},
+		{Type: UnexpectedOU, Path: "Root/Legacy", Id: "ou-2"},
+	}, findings)
+}
+
+// TestCheckNoDrift tests that nothing is found if the organization matches the
+// layout, ignoring accounts the layout doesn't list.
+func TestCheckNoDrift(t *testing.T) {
+	desired := &Layout{
+		Root: OU{
+			Name:     "Root",
+			Accounts: []string{"111111111111"},
+			OUs:      []OU{{Name: "Workloads"}, {Name: "Legacy"}},
+		},
+	}
+	require.Empty(t, Check(desired, newTestTree()))
+}
+
+// TestFindingsReport tests the human readable report of the findings.
+func TestFindingsReport(t *testing.T) {
+	require.Equal(t, "No drift from the layout\n", Findings{}.Report())
+
+	findings := Findings{
+		{Type: MissingOU, Path: "Root/Workloads/Prod"},
+		{Type: MisplacedAccount, Path: "Root/Workloads/Prod", Id: "222222222222", Name: "Prod", Actual: "Root"},
+		{Type: MissingAccount, Path: "Root/Workloads/Prod", Id: "444444444444"},
+		{Type: UnexpectedOU, Path: "Root/Legacy", Id: "ou-2"},
+	}
+	expectedReport := "" +
+		"4 difference(s) from the layout:\n" +
+		"  - missing OU Root/Workloads/Prod\n" +
+		"  - misplaced account Prod (222222222222) is in Root, expected Root/Workloads/Prod\n" +
+		"  - missing account 444444444444, expected in Root/Workloads/Prod\n" +
+		"  - unexpected OU Root/Legacy (ou-2)\n"
+	require.Equal(t, expectedReport, findings.Report())
+
+	jsonFindings, err := findings[:1].ToJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"type":"MISSING_OU","path":"Root/Workloads/Prod"}]`, string(jsonFindings))
+}
@@ -0,0 +1,104 @@
+// # Layout
+//
+// This package contains the code for checking an AWS Organization against its
+// desired layout. The layout is written in YAML as a tree of OUs by name, each
+// listing the IDs of the accounts that should be in it, and is compared with
+// the tree generated in the generation package to find any drift.
+//
+// An example layout:
+//
+//	root:
+//	  accounts: ["111111111111"]
+//	  ous:
+//	    - name: Workloads
+//	      ous:
+//	        - name: Prod
+//	          accounts: ["222222222222"]
+//	    - name: Sandbox
+package layout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Layout ------------------------------------------------------------------
+// Layout is a struct that represents the desired structure of the organization,
+// starting at the root.
+type Layout struct {
+	Root OU `yaml:"root" json:"root"`
+}
+
+// --- OU ----------------------------------------------------------------------
+// OU is a struct that represents an OU in the desired structure. OUs are
+// matched with the organization by their path of names from the root, and
+// accounts by their IDs. Accounts that aren't listed anywhere in the layout are
+// allowed to be in any OU.
+type OU struct {
+	Name     string   `yaml:"name" json:"name"`
+	Accounts []string `yaml:"accounts" json:"accounts"`
+	OUs      []OU     `yaml:"ous" json:"ous"`
+}
+
+// Load is a function that parses the desired layout from its YAML
+// representation, checking that no OU or account is listed twice.
+func Load(data []byte) (*Layout, error) {
+	layout := &Layout{}
+	// Reject unknown keys, so that a misspelt option isn't silently ignored.
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err := dec.Decode(layout)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse the layout: %w", err)
+	}
+	if layout.Root.Name == "" {
+		layout.Root.Name = "Root"
+	}
+
+	// Check that the layout is a tree that could exist.
+	accounts := make(map[string]string)
+	var validate func(ou OU, path string) error
+	validate = func(ou OU, path string) error {
+		for _, id := range ou.Accounts {
+			if other, ok := accounts[id]; ok {
+				return fmt.Errorf("invalid layout: account %s is in both %s and %s", id, other, path)
+			}
+			accounts[id] = path
+		}
+		names := make(map[string]bool)
+		for _, child := range ou.OUs {
+			if child.Name == "" {
+				return fmt.Errorf("invalid layout: an OU in %s has no name", path)
+			}
+			if names[child.Name] {
+				return fmt.Errorf("invalid layout: OU %s is in %s more than once", child.Name, path)
+			}
+			names[child.Name] = true
+			err := validate(child, path+"/"+child.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = validate(layout.Root, layout.Root.Name)
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// ReadFromFile is a function that reads the desired layout from the given YAML
+// file.
+func ReadFromFile(filename string) (*Layout, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
@@ -0,0 +1,66 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoad tests that a layout is parsed from YAML, with account IDs kept as
+// written even if they look like numbers.
+func TestLoad(t *testing.T) {
+	layout, err := Load([]byte(`
+root:
+  accounts: ["111111111111"]
+  ous:
+    - name: Workloads
+      accounts: [012345678901]
+      ous:
+        - name: Prod
+    - name: Sandbox
+`))
+	require.NoError(t, err)
+	require.Equal(t, &Layout{
+		Root: OU{
+			Name:     "Root",
+			Accounts: []string{"111111111111"},
+			OUs: []OU{
+				{
+					Name:     "Workloads",
+					Accounts: []string{"012345678901"},
+					OUs:      []OU{{Name: "Prod"}},
+				},
+				{Name: "Sandbox"},
+			},
+		},
+	}, layout)
+}
+
+// TestLoadInvalid tests that layouts that couldn't exist are rejected.
+func TestLoadInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"not YAML":          `root: [`,
+		"unnamed OU":        "root:\n  ous:\n    - accounts: []\n",
+		"duplicate OU":      "root:\n  ous:\n    - name: A\n    - name: A\n",
+		"duplicate account": "root:\n  accounts: [\"1\"]\n  ous:\n    - name: A\n      accounts: [\"1\"]\n",
+		"misspelt key":      "root:\n  ous:\n    - name: A\n      acounts: [\"1\"]\n",
+	}
+	for name, data := range invalid {
+		_, err := Load([]byte(data))
+		require.Error(t, err, name)
+	}
+}
+
+// TestReadFromFile tests that a layout is read from a file.
+func TestReadFromFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "layout.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("root:\n  ous:\n    - name: A\n"), 0o600))
+	layout, err := ReadFromFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "A", layout.Root.OUs[0].Name)
+
+	_, err = ReadFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/json"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/lint"
+)
+
+// The exit codes of the lint command, so that it can be used to gate
+// pipelines on the organization having no findings with the error severity.
+const (
+	lintExitOK     = 0
+	lintExitErrors = 1
+	lintExitError  = 2
+)
+
+// runLint is the entry point of the lint command, it runs the lint rules over
+// the organization, either live or from a snapshot, and outputs the findings in
+// the chosen format. It returns the exit code of the command.
+func runLint(args []string) int {
+	// STAGE 1: Sort out the input flags
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	inputPtr := flags.String("input", "", "A JSON file previously written by this application to lint instead of querying AWS")
+	configPtr := flags.String("config", "", "A YAML file that enables, disables and configures the lint rules")
+	formatPtr := flags.String("format", "text", "The format of the findings, one of text, json or sarif")
+	outputPtr := flags.String("o", "", "The output file for the findings, they are printed if not given")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aws-organizations-visualiser lint [flags]")
+		flags.PrintDefaults()
+		fmt.Fprintln(flags.Output(), "Rules:")
+		for _, rule := range lint.Rules() {
+			fmt.Fprintf(flags.Output(), "  %s\n    \t%s\n", rule.ID(), rule.Description())
+		}
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return lintExitError
+	}
+	if *formatPtr != "text" && *formatPtr != "json" && *formatPtr != "sarif" {
+		fmt.Printf("Unknown format %s, expected text, json or sarif\n", *formatPtr)
+		return lintExitError
+	}
+
+	// STAGE 2: Set up the logging
+	ll := os.Getenv("LOGS_ENABLED")
+	setupLogging(ll)
+
+	// STAGE 3: Get the configuration of the rules and the organization
+	config := &lint.Config{}
+	if *configPtr != "" {
+		var err error
+		config, err = lint.ReadConfigFromFile(*configPtr)
+		if err != nil {
+			fmt.Println("Error reading lint config")
+			logs.Println(err)
+			return lintExitError
+		}
+	}
+	org, err := getOrganization(*inputPtr, generation.Options{})
+	if err != nil {
+		return lintExitError
+	}
+
+	// STAGE 4: Run the rules and output the findings
+	findings, err := lint.Run(org, config)
+	if err != nil {
+		fmt.Println("Error running lint rules")
+		fmt.Println(err)
+		return lintExitError
+	}
+	var output []byte
+	switch *formatPtr {
+	case "json":
+		output, err = findings.ToJSON()
+	case "sarif":
+		output, err = findings.ToSARIF()
+	default:
+		output = []byte(findings.Report())
+	}
+	if err != nil {
+		fmt.Println("Error generating findings")
+		logs.Println(err)
+		return lintExitError
+	}
+	if *outputPtr == "" {
+		fmt.Print(string(output))
+	} else {
+		err = json.OutputToFile(output, *outputPtr)
+		if err != nil {
+			fmt.Println("Error outputting findings to file")
+			logs.Println(err)
+			return lintExitError
+		}
+	}
+	if findings.HasErrors() {
+		return lintExitErrors
+	}
+	return lintExitOK
+}
@@ -0,0 +1,230 @@
+// # Lint
+//
+// This package contains the code for linting an AWS Organization. It runs a
+// set of rules over the tree generated in the generation package, each of
+// which looks for a single kind of problem such as empty OUs or accounts
+// sitting directly in the root.
+//
+// Rules are registered with Register and can be enabled, disabled and
+// configured with a YAML file:
+//
+//	rules:
+//	  accounts-in-root:
+//	    enabled: false
+//	  max-depth:
+//	    maxDepth: 3
+//	    severity: error
+//	  ou-naming:
+//	    pattern: "^[A-Z][A-Za-z0-9-]*$"
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is how serious a finding is, using the levels of SARIF.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+)
+
+// --- Rule --------------------------------------------------------------------
+// Rule is an interface for a single lint rule, so that new rules can be added
+// by registering them.
+type Rule interface {
+	// ID is the unique name of the rule, used to configure it.
+	ID() string
+	// Description is a short sentence describing what the rule looks for.
+	Description() string
+	// DefaultSeverity is the severity of the rule's findings unless it is
+	// configured otherwise.
+	DefaultSeverity() Severity
+	// Check runs the rule over the organization. The rule ID and severity of
+	// the findings returned are filled in by Run.
+	Check(org *generation.Organization, config RuleConfig) ([]Finding, error)
+}
+
+// rules is every registered rule, in the order they are run.
+var rules = make([]Rule, 0)
+
+// Register adds the rule to the rules that are run, it panics if a rule with
+// the same ID has already been registered.
+func Register(rule Rule) {
+	for _, registered := range rules {
+		if registered.ID() == rule.ID() {
+			panic(fmt.Sprintf("lint rule %s is already registered", rule.ID()))
+		}
+	}
+	rules = append(rules, rule)
+}
+
+// Rules returns every registered rule, in the order they are run.
+func Rules() []Rule {
+	return append([]Rule{}, rules...)
+}
+
+// --- Config ------------------------------------------------------------------
+// Config is a struct that represents the configuration of the rules, keyed by
+// their IDs. Rules that aren't configured are run with their defaults.
+type Config struct {
+	Rules map[string]RuleConfig `yaml:"rules"`
+}
+
+// --- RuleConfig --------------------------------------------------------------
+// RuleConfig is a struct that represents the configuration of a single rule.
+// Only the options relevant to the rule are used.
+type RuleConfig struct {
+	// Enabled turns the rule on or off, rules are enabled unless set to false.
+	Enabled *bool `yaml:"enabled"`
+	// Severity overrides the default severity of the rule's findings.
+	Severity Severity `yaml:"severity"`
+	// MaxDepth is the deepest an OU can be nested below the root.
+	MaxDepth int `yaml:"maxDepth"`
+	// Pattern is the regular expression that every OU name must match.
+	Pattern string `yaml:"pattern"`
+	// QuarantineOUs is the names of the OUs that suspended accounts belong in.
+	QuarantineOUs []string `yaml:"quarantineOUs"`
+}
+
+// enabled returns whether the rule should be run.
+func (c RuleConfig) enabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// LoadConfig is a function that parses the configuration of the rules from its
+// YAML representation, checking that every rule and severity is known.
+func LoadConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	// Reject unknown keys, so that a misspelt option isn't silently ignored.
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err := dec.Decode(config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse the lint config: %w", err)
+	}
+	for id, ruleConfig := range config.Rules {
+		if findRule(id) == nil {
+			return nil, fmt.Errorf("invalid lint config: unknown rule %s", id)
+		}
+		switch ruleConfig.Severity {
+		case "", SeverityError, SeverityWarning, SeverityNote:
+		default:
+			return nil, fmt.Errorf("invalid lint config: unknown severity %s for rule %s", ruleConfig.Severity, id)
+		}
+	}
+	return config, nil
+}
+
+// ReadConfigFromFile is a function that reads the configuration of the rules
+// from the given YAML file.
+func ReadConfigFromFile(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return LoadConfig(data)
+}
+
+// findRule returns the registered rule with the given ID, or nil if there
+// isn't one.
+func findRule(id string) Rule {
+	for _, rule := range rules {
+		if rule.ID() == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+// --- Finding -----------------------------------------------------------------
+// Finding is a struct that represents a single problem found by a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Path is the path of names from the root to the OU or account.
+	Path string `json:"path"`
+	// Id is the ID of the OU or account.
+	Id      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// String returns a human readable description of the finding.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s (%s): %s", f.Severity, f.Rule, f.Path, f.Id, f.Message)
+}
+
+// --- Findings ----------------------------------------------------------------
+// Findings is the list of every problem found by the rules.
+type Findings []Finding
+
+// ToJSON returns a JSON representation of the findings.
+func (f Findings) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Report returns a human readable report of the findings, one per line.
+func (f Findings) Report() string {
+	if len(f) == 0 {
+		return "No lint findings\n"
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "%d lint finding(s):\n", len(f))
+	for _, finding := range f {
+		fmt.Fprintf(&report, "  - %s\n", finding)
+	}
+	return report.String()
+}
+
+// HasErrors returns whether any of the findings has the error severity.
+func (f Findings) HasErrors() bool {
+	for _, finding := range f {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs every enabled rule over the organization and returns their
+// findings, in the order the rules were registered.
+func Run(org *generation.Organization, config *Config) (Findings, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	findings := make(Findings, 0)
+	for _, rule := range rules {
+		ruleConfig := config.Rules[rule.ID()]
+		if !ruleConfig.enabled() {
+			continue
+		}
+		severity := ruleConfig.Severity
+		if severity == "" {
+			severity = rule.DefaultSeverity()
+		}
+
+		ruleFindings, err := rule.Check(org, ruleConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run lint rule %s: %w", rule.ID(), err)
+		}
+		for _, finding := range ruleFindings {
+			finding.Rule = rule.ID()
+			finding.Severity = severity
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
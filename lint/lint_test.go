@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRun tests that every enabled rule is run with its configuration, and
+// that the rule and severity of each finding are filled in.
+func TestRun(t *testing.T) {
+	org := newTestOrganization()
+
+	findings, err := Run(org, nil)
+	require.NoError(t, err)
+	require.Equal(t, Findings{
+		{Rule: "accounts-in-root", Severity: SeverityWarning, Path: "Root/Stray", Id: "222222222222", Message: "account is directly in the root"},
+		{Rule: "empty-ous", Severity: SeverityWarning, Path: "Root/Empty", Id: "ou-5", Message: "OU is empty"},
+		{Rule: "suspended-accounts", Severity: SeverityWarning, Path: "Root/Workloads/Closed", Id: "444444444444", Message: "suspended account is not in a quarantine OU"},
+		{Rule: "duplicate-account-names", Severity: SeverityError, Path: "Root/Workloads/deep_one/Deeper/Prod", Id: "555555555555", Message: "account name is also used by 333333333333"},
+	}, findings)
+	require.True(t, findings.HasErrors())
+
+	config, err := LoadConfig([]byte(`
+rules:
+  accounts-in-root:
+    enabled: false
+  empty-ous:
+    enabled: false
+  suspended-accounts:
+    enabled: false
+  duplicate-account-names:
+    severity: note
+  max-depth:
+    maxDepth: 2
+    severity: error
+`))
+	require.NoError(t, err)
+	findings, err = Run(org, config)
+	require.NoError(t, err)
+	require.Equal(t, Findings{
+		{Rule: "max-depth", Severity: SeverityError, Path: "Root/Workloads/deep_one/Deeper", Id: "ou-3", Message: "OU is nested 3 levels deep, more than the maximum of 2"},
+		{Rule: "duplicate-account-names", Severity: SeverityNote, Path: "Root/Workloads/deep_one/Deeper/Prod", Id: "555555555555", Message: "account name is also used by 333333333333"},
+	}, findings)
+
+	// Errors from the rules are returned.
+	_, err = Run(org, &Config{Rules: map[string]RuleConfig{"ou-naming": {Pattern: "["}}})
+	require.ErrorContains(t, err, "ou-naming")
+}
+
+// TestLoadConfigInvalid tests that configs with unknown rules or severities
+// are rejected.
+func TestLoadConfigInvalid(t *testing.T) {
+	for _, data := range []string{
+		"rules: [",
+		"rules:\n  not-a-rule:\n    enabled: false\n",
+		"rules:\n  empty-ous:\n    severity: fatal\n",
+		"rules:\n  max-depth:\n    maxdepth: 2\n",
+		"rules:\n  suspended-accounts:\n    quarantineOus: [Closed]\n",
+		"rules:\n  empty-ous:\n    enabeld: false\n",
+	} {
+		_, err := LoadConfig([]byte(data))
+		require.Error(t, err, data)
+	}
+
+	filename := filepath.Join(t.TempDir(), "lint.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("rules:\n  empty-ous:\n    enabled: false\n"), 0o600))
+	config, err := ReadConfigFromFile(filename)
+	require.NoError(t, err)
+	require.False(t, config.Rules["empty-ous"].enabled())
+}
+
+// testRule is a rule registered by the tests to check rules can be added.
+type testRule struct{}
+
+func (testRule) ID() string                { return "test-rule" }
+func (testRule) Description() string       { return "A rule for the tests." }
+func (testRule) DefaultSeverity() Severity { return SeverityNote }
+func (testRule) Check(org *generation.Organization, config RuleConfig) ([]Finding, error) {
+	return []Finding{{Path: org.Root.Name, Id: org.Root.Id, Message: "test finding"}}, nil
+}
+
+// TestRegister tests that registered rules are run and that a rule can't be
+// registered twice.
+func TestRegister(t *testing.T) {
+	registered := rules
+	defer func() { rules = registered }()
+
+	Register(testRule{})
+	require.Panics(t, func() { Register(testRule{}) })
+	require.Equal(t, "test-rule", Rules()[len(Rules())-1].ID())
+
+	findings, err := Run(newTestOrganization(), nil)
+	require.NoError(t, err)
+	require.Equal(t, Finding{Rule: "test-rule", Severity: SeverityNote, Path: "Root", Id: "r-1234", Message: "test finding"}, findings[len(findings)-1])
+}
+
+// TestReport tests the human readable report of the findings.
+func TestReport(t *testing.T) {
+	require.Equal(t, "No lint findings\n", Findings{}.Report())
+	require.False(t, Findings{}.HasErrors())
+
+	findings := Findings{
+		{Rule: "empty-ous", Severity: SeverityWarning, Path: "Root/Empty", Id: "ou-5", Message: "OU is empty"},
+	}
+	require.Equal(t, "1 lint finding(s):\n  - warning [empty-ous] Root/Empty (ou-5): OU is empty\n", findings.Report())
+}
+
+// TestToSARIF tests that the findings are output as a SARIF log with each
+// rule and a result for each finding.
+func TestToSARIF(t *testing.T) {
+	findings := Findings{
+		{Rule: "empty-ous", Severity: SeverityWarning, Path: "Root/Empty", Id: "ou-5", Message: "OU is empty"},
+		{Rule: "accounts-in-root", Severity: SeverityError, Path: "Root/Stray", Id: "222222222222", Message: "account is directly in the root"},
+	}
+	data, err := findings.ToSARIF()
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Tool.Driver.Rules, len(rules))
+	require.Equal(t, "accounts-in-root", log.Runs[0].Tool.Driver.Rules[0].Id)
+	require.Equal(t, []sarifResult{
+		{
+			RuleId:    "empty-ous",
+			Level:     SeverityWarning,
+			Message:   sarifMessage{Text: "OU is empty"},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{Name: "Empty", FullyQualifiedName: "Root/Empty", Kind: "organizationalUnit"}}}},
+		},
+		{
+			RuleId:    "accounts-in-root",
+			Level:     SeverityError,
+			Message:   sarifMessage{Text: "account is directly in the root"},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{Name: "Stray", FullyQualifiedName: "Root/Stray", Kind: "account"}}}},
+		},
+	}, log.Runs[0].Results)
+}
@@ -0,0 +1,215 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// DefaultMaxDepth is the deepest an OU can be nested below the root unless
+// configured otherwise.
+const DefaultMaxDepth = 4
+
+// DefaultQuarantineOUs is the names of the OUs that suspended accounts belong
+// in unless configured otherwise.
+var DefaultQuarantineOUs = []string{"Suspended"}
+
+// init registers the built in rules.
+func init() {
+	Register(accountsInRoot{})
+	Register(emptyOUs{})
+	Register(maxDepth{})
+	Register(ouNaming{})
+	Register(suspendedAccounts{})
+	Register(duplicateAccountNames{})
+}
+
+// visit calls the function for each OU in the tree below the root, along with
+// its path and depth, and for each account with the OU it is in.
+func visit(root *generation.OU, ouFn func(ou *generation.OU, path string, depth int), accountFn func(account generation.Account, ou *generation.OU, path string)) {
+	var walk func(ou *generation.OU, path string, depth int)
+	walk = func(ou *generation.OU, path string, depth int) {
+		if depth > 0 && ouFn != nil {
+			ouFn(ou, path, depth)
+		}
+		for _, account := range ou.Accounts {
+			if accountFn != nil {
+				accountFn(account, ou, path+"/"+aws.ToString(account.Name))
+			}
+		}
+		for _, child := range ou.Children {
+			walk(child, path+"/"+child.Name, depth+1)
+		}
+	}
+	walk(root, root.Name, 0)
+}
+
+// --- accounts-in-root --------------------------------------------------------
+// accountsInRoot finds accounts other than the management account that sit
+// directly in the root rather than in an OU.
+type accountsInRoot struct{}
+
+func (accountsInRoot) ID() string { return "accounts-in-root" }
+func (accountsInRoot) Description() string {
+	return "Accounts other than the management account should be in an OU."
+}
+func (accountsInRoot) DefaultSeverity() Severity { return SeverityWarning }
+
+func (accountsInRoot) Check(org *generation.Organization, config RuleConfig) ([]Finding, error) {
+	findings := make([]Finding, 0)
+	for _, account := range org.Root.Accounts {
+		id := aws.ToString(account.Id)
+		if id == org.ManagementAccountId {
+			continue
+		}
+		findings = append(findings, Finding{
+			Path:    org.Root.Name + "/" + aws.ToString(account.Name),
+			Id:      id,
+			Message: "account is directly in the root",
+		})
+	}
+	return findings, nil
+}
+
+// --- empty-ous ---------------------------------------------------------------
+// emptyOUs finds OUs that have no accounts or child OUs.
+type emptyOUs struct{}
+
+func (emptyOUs) ID() string                { return "empty-ous" }
+func (emptyOUs) Description() string       { return "OUs should hold accounts or other OUs." }
+func (emptyOUs) DefaultSeverity() Severity { return SeverityWarning }
+
+func (emptyOUs) Check(org *generation.Organization, config RuleConfig) ([]Finding, error) {
+	findings := make([]Finding, 0)
+	visit(org.Root, func(ou *generation.OU, path string, depth int) {
+		if len(ou.Accounts) == 0 && len(ou.Children) == 0 {
+			findings = append(findings, Finding{Path: path, Id: ou.Id, Message: "OU is empty"})
+		}
+	}, nil)
+	return findings, nil
+}
+
+// --- max-depth ---------------------------------------------------------------
+// maxDepth finds OUs nested deeper below the root than the configured maximum.
+type maxDepth struct{}
+
+func (maxDepth) ID() string { return "max-depth" }
+func (maxDepth) Description() string {
+	return "OUs shouldn't be nested deeper than the maximum depth."
+}
+func (maxDepth) DefaultSeverity() Severity { return SeverityWarning }
+
+func (maxDepth) Check(org *generation.Organization, config RuleConfig) ([]Finding, error) {
+	max := config.MaxDepth
+	if max <= 0 {
+		max = DefaultMaxDepth
+	}
+
+	findings := make([]Finding, 0)
+	visit(org.Root, func(ou *generation.OU, path string, depth int) {
+		if depth > max {
+			findings = append(findings, Finding{
+				Path:    path,
+				Id:      ou.Id,
+				Message: fmt.Sprintf("OU is nested %d levels deep, more than the maximum of %d", depth, max),
+			})
+		}
+	}, nil)
+	return findings, nil
+}
+
+// --- ou-naming ---------------------------------------------------------------
+// ouNaming finds OUs whose names don't match the configured pattern, it finds
+// nothing if no pattern is configured.
+type ouNaming struct{}
+
+func (ouNaming) ID() string                { return "ou-naming" }
+func (ouNaming) Description() string       { return "OU names should match the naming pattern." }
+func (ouNaming) DefaultSeverity() Severity { return SeverityWarning }
+
+func (ouNaming) Check(org *generation.Organization, config RuleConfig) ([]Finding, error) {
+	findings := make([]Finding, 0)
+	if config.Pattern == "" {
+		return findings, nil
+	}
+	pattern, err := regexp.Compile(config.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	visit(org.Root, func(ou *generation.OU, path string, depth int) {
+		if !pattern.MatchString(ou.Name) {
+			findings = append(findings, Finding{
+				Path:    path,
+				Id:      ou.Id,
+				Message: fmt.Sprintf("OU name doesn't match the pattern %s", config.Pattern),
+			})
+		}
+	}, nil)
+	return findings, nil
+}
+
+// --- suspended-accounts ------------------------------------------------------
+// suspendedAccounts finds suspended accounts that haven't been moved into one
+// of the quarantine OUs.
+type suspendedAccounts struct{}
+
+func (suspendedAccounts) ID() string { return "suspended-accounts" }
+func (suspendedAccounts) Description() string {
+	return "Suspended accounts should be moved into a quarantine OU."
+}
+func (suspendedAccounts) DefaultSeverity() Severity { return SeverityWarning }
+
+func (suspendedAccounts) Check(org *generation.Organization, config RuleConfig) ([]Finding, error) {
+	quarantine := config.QuarantineOUs
+	if len(quarantine) == 0 {
+		quarantine = DefaultQuarantineOUs
+	}
+	isQuarantine := make(map[string]bool, len(quarantine))
+	for _, name := range quarantine {
+		isQuarantine[name] = true
+	}
+
+	findings := make([]Finding, 0)
+	visit(org.Root, nil, func(account generation.Account, ou *generation.OU, path string) {
+		if account.Status == types.AccountStatusSuspended && !isQuarantine[ou.Name] {
+			findings = append(findings, Finding{
+				Path:    path,
+				Id:      aws.ToString(account.Id),
+				Message: "suspended account is not in a quarantine OU",
+			})
+		}
+	})
+	return findings, nil
+}
+
+// --- duplicate-account-names -------------------------------------------------
+// duplicateAccountNames finds accounts that share their name with an account
+// found earlier in the tree.
+type duplicateAccountNames struct{}
+
+func (duplicateAccountNames) ID() string                { return "duplicate-account-names" }
+func (duplicateAccountNames) Description() string       { return "Account names should be unique." }
+func (duplicateAccountNames) DefaultSeverity() Severity { return SeverityError }
+
+func (duplicateAccountNames) Check(org *generation.Organization, config RuleConfig) ([]Finding, error) {
+	seen := make(map[string]string)
+	findings := make([]Finding, 0)
+	visit(org.Root, nil, func(account generation.Account, ou *generation.OU, path string) {
+		name := aws.ToString(account.Name)
+		id := aws.ToString(account.Id)
+		if other, ok := seen[name]; ok {
+			findings = append(findings, Finding{
+				Path:    path,
+				Id:      id,
+				Message: fmt.Sprintf("account name is also used by %s", other),
+			})
+			return
+		}
+		seen[name] = id
+	})
+	return findings, nil
+}
@@ -0,0 +1,119 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOrganization creates an organization that breaks each of the built
+// in rules once.
+func newTestOrganization() *generation.Organization {
+	return &generation.Organization{
+		ManagementAccountId: "111111111111",
+		Root: &generation.OU{
+			Id:   "r-1234",
+			Name: "Root",
+			Accounts: []generation.Account{
+				{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management"), Status: types.AccountStatusActive}},
+				{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Stray"), Status: types.AccountStatusActive}},
+			},
+			Children: []*generation.OU{
+				{
+					Id:   "ou-1",
+					Name: "Workloads",
+					Accounts: []generation.Account{
+						{Account: types.Account{Id: aws.String("333333333333"), Name: aws.String("Prod"), Status: types.AccountStatusActive}},
+						{Account: types.Account{Id: aws.String("444444444444"), Name: aws.String("Closed"), Status: types.AccountStatusSuspended}},
+					},
+					Children: []*generation.OU{
+						{
+							Id:   "ou-2",
+							Name: "deep_one",
+							Children: []*generation.OU{
+								{
+									Id:       "ou-3",
+									Name:     "Deeper",
+									Accounts: []generation.Account{{Account: types.Account{Id: aws.String("555555555555"), Name: aws.String("Prod"), Status: types.AccountStatusActive}}},
+								},
+							},
+						},
+					},
+				},
+				{
+					Id:   "ou-4",
+					Name: "Suspended",
+					Accounts: []generation.Account{
+						{Account: types.Account{Id: aws.String("666666666666"), Name: aws.String("Old"), Status: types.AccountStatusSuspended}},
+					},
+				},
+				{Id: "ou-5", Name: "Empty"},
+			},
+		},
+	}
+}
+
+// TestRules tests each of the built in rules on their own.
+func TestRules(t *testing.T) {
+	org := newTestOrganization()
+	tests := []struct {
+		rule     Rule
+		config   RuleConfig
+		expected []Finding
+	}{
+		{
+			rule:     accountsInRoot{},
+			expected: []Finding{{Path: "Root/Stray", Id: "222222222222", Message: "account is directly in the root"}},
+		},
+		{
+			rule:     emptyOUs{},
+			expected: []Finding{{Path: "Root/Empty", Id: "ou-5", Message: "OU is empty"}},
+		},
+		{
+			rule:     maxDepth{},
+			expected: []Finding{},
+		},
+		{
+			rule:     maxDepth{},
+			config:   RuleConfig{MaxDepth: 2},
+			expected: []Finding{{Path: "Root/Workloads/deep_one/Deeper", Id: "ou-3", Message: "OU is nested 3 levels deep, more than the maximum of 2"}},
+		},
+		{
+			rule:     ouNaming{},
+			expected: []Finding{},
+		},
+		{
+			rule:     ouNaming{},
+			config:   RuleConfig{Pattern: "^[A-Z][A-Za-z]*$"},
+			expected: []Finding{{Path: "Root/Workloads/deep_one", Id: "ou-2", Message: "OU name doesn't match the pattern ^[A-Z][A-Za-z]*$"}},
+		},
+		{
+			rule:     suspendedAccounts{},
+			expected: []Finding{{Path: "Root/Workloads/Closed", Id: "444444444444", Message: "suspended account is not in a quarantine OU"}},
+		},
+		{
+			rule:   suspendedAccounts{},
+			config: RuleConfig{QuarantineOUs: []string{"Workloads"}},
+			expected: []Finding{
+				{Path: "Root/Suspended/Old", Id: "666666666666", Message: "suspended account is not in a quarantine OU"},
+			},
+		},
+		{
+			rule:     duplicateAccountNames{},
+			expected: []Finding{{Path: "Root/Workloads/deep_one/Deeper/Prod", Id: "555555555555", Message: "account name is also used by 333333333333"}},
+		},
+	}
+
+	for _, test := range tests {
+		findings, err := test.rule.Check(org, test.config)
+		require.NoError(t, err, test.rule.ID())
+		require.Equal(t, test.expected, findings, test.rule.ID())
+	}
+
+	// An invalid naming pattern is reported.
+	_, err := ouNaming{}.Check(org, RuleConfig{Pattern: "["})
+	require.Error(t, err)
+}
@@ -0,0 +1,112 @@
+package lint
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// The schema and version of SARIF that the findings are output in.
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// sarifLog and the types below are the parts of a SARIF log that are needed
+// to report the findings, so they can be shown by tools such as code scanning.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level Severity `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     Severity        `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// ToSARIF returns a SARIF representation of the findings. As the findings
+// aren't in files, each one is located by the path of the OU or account it is
+// about.
+func (f Findings) ToSARIF() ([]byte, error) {
+	driver := sarifDriver{
+		Name:           "aws-organizations-visualiser",
+		InformationUri: "https://github.com/CentricaDevOps/aws-organizations-visualiser",
+		Rules:          make([]sarifRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			Id:                   rule.ID(),
+			ShortDescription:     sarifMessage{Text: rule.Description()},
+			DefaultConfiguration: sarifConfiguration{Level: rule.DefaultSeverity()},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(f))
+	for _, finding := range f {
+		// Account IDs are all digits, OU and root IDs start with a prefix.
+		kind := "account"
+		if strings.HasPrefix(finding.Id, "ou-") || strings.HasPrefix(finding.Id, "r-") {
+			kind = "organizationalUnit"
+		}
+		name := finding.Path[strings.LastIndex(finding.Path, "/")+1:]
+		results = append(results, sarifResult{
+			RuleId:  finding.Rule,
+			Level:   finding.Severity,
+			Message: sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               name,
+					FullyQualifiedName: finding.Path,
+					Kind:               kind,
+				}},
+			}},
+		})
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunLint tests that the lint command outputs the findings in the chosen
+// format, with a non-zero exit code if any has the error severity.
+func TestRunLint(t *testing.T) {
+	dir := t.TempDir()
+	snapshotFile := filepath.Join(dir, "snapshot.json")
+	configFile := filepath.Join(dir, "lint.yaml")
+	require.NoError(t, os.WriteFile(snapshotFile, []byte(`{"id":"o-1234","root":{"id":"r-1234","name":"Root","children":[{"id":"ou-1","name":"Empty","children":[],"accounts":[]}],"accounts":[]}}`), 0o600))
+
+	var code int
+	output := captureOutput(func() {
+		code = runLint([]string{"-input", snapshotFile})
+	})
+	require.Equal(t, "1 lint finding(s):\n  - warning [empty-ous] Root/Empty (ou-1): OU is empty\n", output)
+	require.Equal(t, lintExitOK, code)
+
+	// The severity can be raised in the config, and the findings written as
+	// JSON to a file.
+	require.NoError(t, os.WriteFile(configFile, []byte("rules:\n  empty-ous:\n    severity: error\n"), 0o600))
+	outputFile := filepath.Join(dir, "lint.json")
+	output = captureOutput(func() {
+		code = runLint([]string{"-input", snapshotFile, "-config", configFile, "-format", "json", "-o", outputFile})
+	})
+	require.Equal(t, "", output)
+	require.Equal(t, lintExitErrors, code)
+	jsonFindings, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"rule":"empty-ous","severity":"error","path":"Root/Empty","id":"ou-1","message":"OU is empty"}]`, string(jsonFindings))
+
+	// Unknown formats are rejected.
+	output = captureOutput(func() {
+		code = runLint([]string{"-input", snapshotFile, "-format", "xml"})
+	})
+	require.Equal(t, "Unknown format xml, expected text, json or sarif\n", output)
+	require.Equal(t, lintExitError, code)
+}
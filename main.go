@@ -24,8 +24,156 @@
 //		      Include the JSON representation of the AWS Organizations structure in the output (default true)
 //		-include-visual
 //		      Include the visual representation of the AWS Organizations structure in the output (default true)
+//		-policy-types string
+//		      A comma separated list of the policy types to include for the root, OUs and accounts, or ALL. The
+//		      types are SERVICE_CONTROL_POLICY, TAG_POLICY, BACKUP_POLICY, AISERVICES_OPT_OUT_POLICY and
+//		      CHATBOT_POLICY (default "")
+//		-effective-policies
+//		      Include the effective policy of each of the policy types for every account in the output (default false)
+//		-include-tags
+//		      Include the tags on the root, OUs and accounts in the output (default false)
+//		-show-tags string
+//		      A comma separated list of tag keys to show next to the names in the visual output, implies -include-tags (default "")
+//		-include-delegated-admins
+//		      Include the delegated administrators and the services with trusted access to the organization in the output (default false)
+//		-format string
+//		      The format of the visual output, one of tree, dot, mermaid, markdown, csv or tsv (default "tree")
+//		-show-accounts
+//		      Include each account in the visual output, for the tree, dot and mermaid formats (default false)
+//		-show-account-ids
+//		      Include the ID of each account shown in the visual output, for the mermaid format (default false)
+//		-max-depth int
+//		      The number of levels of OUs below the root to show in the visual output, summarising the OUs below
+//		      them, for the tree, dot, mermaid and markdown formats, or 0 for every level (default 0)
+//		-sort string
+//		      The order to sort the OUs and accounts by, one of name, id or accounts, which puts the OUs with the
+//		      most accounts below them first. They are left in the order AWS returns them if not given (default "")
+//		-dot-clusters
+//		      Draw each OU as a box around its accounts and child OUs in the dot format (default false)
+//		-mermaid-diagram string
+//		      The kind of diagram to draw in the mermaid format, one of flowchart or mindmap (default "flowchart")
+//		-csv-columns string
+//		      A comma separated list of the columns to include in the csv and tsv formats, from id, name, email,
+//		      status, joined_method, joined_timestamp, ou_path and ou_id (default "", every column)
+//		-csv-delimiter string
+//		      The character that separates the fields in the csv format (default ",")
+//		-show-totals
+//		      Include the total number of accounts below each OU in the tree format, with how many have each
+//		      status (default false)
+//		-colour string
+//		      When to colour the tree format, one of auto, always or never. With auto the tree is coloured if the
+//		      output is a terminal and the NO_COLOR environment variable isn't set (default "auto")
+//		-ascii
+//		      Draw the tree format with ASCII characters rather than box-drawing characters (default false)
+//		-html string
+//		      An output file for a self contained HTML explorer of the AWS Organizations structure (default "")
+//		-input string
+//		      A JSON file previously written by this application to display instead of querying AWS. The JSON
+//		      output can't be written to the same file (default "")
 //		-o string
 //		      The output file for the JSON representation of the AWS Organizations structure (default "output.json")
+//		-parallelism int
+//		      The maximum number of AWS API calls to make at the same time (default 8)
+//		-max-attempts int
+//		      The maximum number of times to attempt an AWS API call that is being throttled (default 5)
+//		-retry-base-delay duration
+//		      The base delay for the exponential backoff between throttled AWS API calls (default 500ms)
+//		-retry-max-delay duration
+//		      The maximum delay between throttled AWS API calls (default 20s)
+//
+// ### Commands
+//
+// Diff compares an old snapshot written by this tool with either a new
+// snapshot or, if only one is given, the live organization. It matches OUs and
+// accounts by their IDs and reports those that were added, removed, renamed,
+// moved or had their status changed.
+//
+//	aws-organizations-visualiser diff [flags] old.json [new.json]
+//
+// Flags:
+//
+//	-include-json
+//	      Include the JSON list of changes in the output (default true)
+//	-include-visual
+//	      Include the human readable report of changes in the output (default true)
+//	-o string
+//	      The output file for the JSON list of changes (default "diff.json")
+//
+// Check compares the organization with the desired layout in a YAML file and
+// reports missing OUs, unexpected OUs and accounts that aren't in the OU the
+// layout lists them in. It exits with 1 if there is any drift and 2 if the
+// check couldn't be run.
+//
+//	aws-organizations-visualiser check [flags] layout.yaml
+//
+// Flags:
+//
+//	-input string
+//	      A JSON file previously written by this application to check instead of querying AWS (default "")
+//	-include-json
+//	      Include the JSON list of differences from the layout in the output (default true)
+//	-include-visual
+//	      Include the human readable report of differences from the layout in the output (default true)
+//	-o string
+//	      The output file for the JSON list of differences from the layout (default "check.json")
+//
+// Plan works out the fewest steps needed to make the organization match the
+// desired layout in a YAML file, creating the missing OUs, moving the accounts
+// the layout lists and deleting the OUs not in the layout. It is a dry run and
+// never makes any changes.
+//
+//	aws-organizations-visualiser plan [flags] layout.yaml
+//
+// Flags:
+//
+//	-input string
+//	      A JSON file previously written by this application to plan from instead of querying AWS (default "")
+//	-include-json
+//	      Include the JSON plan in the output (default true)
+//	-include-visual
+//	      Include the human readable plan in the output (default true)
+//	-o string
+//	      The output file for the JSON plan (default "plan.json")
+//
+// Apply runs each step of a reviewed plan written by the plan command, asking
+// for confirmation before each one. The completed steps are recorded in a
+// journal so that a run that fails or is stopped can be resumed by running the
+// command again. A step that AWS completed before the run failed is picked up
+// rather than repeated, and throttled calls are retried.
+//
+//	aws-organizations-visualiser apply [flags] plan.json
+//
+// Flags:
+//
+//	-yes
+//	      Run every step without asking for confirmation (default false)
+//	-journal string
+//	      The file that records the completed steps so a failed run can be resumed, defaults to the plan file with .journal added (default "")
+//	-max-attempts int
+//	      The maximum number of times to attempt an AWS API call that is being throttled (default 5)
+//	-retry-base-delay duration
+//	      The base delay for the exponential backoff between throttled AWS API calls (default 500ms)
+//	-retry-max-delay duration
+//	      The maximum delay between throttled AWS API calls (default 20s)
+//
+// Lint runs a set of rules over the organization and reports what they find:
+// accounts directly in the root, empty OUs, OUs nested too deeply, OU names not
+// matching a pattern, suspended accounts outside a quarantine OU and duplicate
+// account names. It exits with 1 if any finding has the error severity and 2 if
+// the rules couldn't be run.
+//
+//	aws-organizations-visualiser lint [flags]
+//
+// Flags:
+//
+//	-input string
+//	      A JSON file previously written by this application to lint instead of querying AWS (default "")
+//	-config string
+//	      A YAML file that enables, disables and configures the lint rules (default "")
+//	-format string
+//	      The format of the findings, one of text, json or sarif (default "text")
+//	-o string
+//	      The output file for the findings, they are printed if not given (default "")
 package main
 
 import (
@@ -34,9 +182,15 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/CentricaDevOps/aws-organizations-visualiser/display/cli"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/csv"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/dot"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/html"
 	"github.com/CentricaDevOps/aws-organizations-visualiser/display/json"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/markdown"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/mermaid"
 	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
@@ -94,55 +248,259 @@ func checkPermissions() (context.Context, *organizations.Client, error) {
 	return ctx, orgClient, nil
 }
 
+// getOrganization gets the organization from the given JSON snapshot, or from
+// AWS if no snapshot is given. Any error is printed before it is returned.
+func getOrganization(input string, opts generation.Options) (*generation.Organization, error) {
+	if input != "" {
+		logs.Println("Input file:", input)
+		org, err := json.ReadFromFile(input)
+		if err != nil {
+			fmt.Println("Error reading input file")
+			logs.Println(err)
+			return nil, err
+		}
+		return org, nil
+	}
+
+	ctx, cfg, err := checkPermissions()
+	if err != nil {
+		fmt.Println("Error checking permissions")
+		logs.Println(err)
+		return nil, err
+	}
+	org, err := generation.GenerateStructure(ctx, cfg, opts)
+	if err != nil {
+		fmt.Println("Error generating structure")
+		logs.Println(err)
+		return nil, err
+	}
+	return org, nil
+}
+
+// sameFile returns whether the two paths are the same existing file, so that a
+// snapshot given with -input isn't overwritten by the JSON output.
+func sameFile(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
+
+// splitList splits a comma separated flag value into its trimmed, non-empty
+// parts.
+func splitList(list string) []string {
+	parts := make([]string, 0)
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
 // main is the entry point of the application, it is called when the application
 // is executed and is used to call the main logic of the application.
 func main() {
+	// Run the command given as the first argument, if any
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		case "plan":
+			runPlan(os.Args[2:])
+			return
+		case "apply":
+			os.Exit(runApply(os.Args[2:]))
+		case "lint":
+			os.Exit(runLint(os.Args[2:]))
+		}
+	}
+
 	// STAGE 1: Sort out the input flags
 	removeSuspendedAccountsPtr := flag.Bool("remove-suspended-accounts", false, "Remove suspended accounts from the output")
 	jsonPtr := flag.Bool("include-json", true, "Include the JSON representation of the AWS Organizations structure in the output")
 	visualPtr := flag.Bool("include-visual", true, "Include the visual representation of the AWS Organizations structure in the output")
+	policyTypesPtr := flag.String("policy-types", "", "A comma separated list of the policy types to include for the root, OUs and accounts, or ALL")
+	effectivePoliciesPtr := flag.Bool("effective-policies", false, "Include the effective policy of each of the policy types for every account in the output")
+	includeTagsPtr := flag.Bool("include-tags", false, "Include the tags on the root, OUs and accounts in the output")
+	showTagsPtr := flag.String("show-tags", "", "A comma separated list of tag keys to show next to the names in the visual output, implies -include-tags")
+	delegatedAdminsPtr := flag.Bool("include-delegated-admins", false, "Include the delegated administrators and the services with trusted access to the organization in the output")
+	formatPtr := flag.String("format", "tree", "The format of the visual output, one of tree, dot, mermaid, markdown, csv or tsv")
+	showAccountsPtr := flag.Bool("show-accounts", false, "Include each account in the visual output, for the tree, dot and mermaid formats")
+	showAccountIdsPtr := flag.Bool("show-account-ids", false, "Include the ID of each account shown in the visual output, for the mermaid format")
+	maxDepthPtr := flag.Int("max-depth", 0, "The number of levels of OUs below the root to show in the visual output, summarising the OUs below them, for the tree, dot, mermaid and markdown formats, or 0 for every level")
+	sortPtr := flag.String("sort", "", "The order to sort the OUs and accounts by, one of name, id or accounts")
+	dotClustersPtr := flag.Bool("dot-clusters", false, "Draw each OU as a box around its accounts and child OUs in the dot format")
+	mermaidDiagramPtr := flag.String("mermaid-diagram", "flowchart", "The kind of diagram to draw in the mermaid format, one of flowchart or mindmap")
+	csvColumnsPtr := flag.String("csv-columns", "", "A comma separated list of the columns to include in the csv and tsv formats, or every column if empty")
+	csvDelimiterPtr := flag.String("csv-delimiter", ",", "The character that separates the fields in the csv format")
+	showTotalsPtr := flag.Bool("show-totals", false, "Include the total number of accounts below each OU in the tree format, with how many have each status")
+	colourPtr := flag.String("colour", "auto", "When to colour the tree format, one of auto, always or never")
+	asciiPtr := flag.Bool("ascii", false, "Draw the tree format with ASCII characters rather than box-drawing characters")
+	htmlPtr := flag.String("html", "", "An output file for a self contained HTML explorer of the AWS Organizations structure")
+	inputPtr := flag.String("input", "", "A JSON file previously written by this application to display instead of querying AWS")
 	outputPtr := flag.String("o", "output.json", "The output file for the JSON representation of the AWS Organizations structure")
+	parallelismPtr := flag.Int("parallelism", generation.DefaultParallelism, "The maximum number of AWS API calls to make at the same time")
+	maxAttemptsPtr := flag.Int("max-attempts", generation.DefaultRetryPolicy.MaxAttempts, "The maximum number of times to attempt an AWS API call that is being throttled")
+	retryBaseDelayPtr := flag.Duration("retry-base-delay", generation.DefaultRetryPolicy.BaseDelay, "The base delay for the exponential backoff between throttled AWS API calls")
+	retryMaxDelayPtr := flag.Duration("retry-max-delay", generation.DefaultRetryPolicy.MaxDelay, "The maximum delay between throttled AWS API calls")
 	flag.Parse()
-
-	// STAGE 2: Set up the logging and check permissions
-	ll := os.Getenv("LOGS_ENABLED")
-	setupLogging(ll)
-	ctx, cfg, err := checkPermissions()
+	policyTypes, err := generation.ParsePolicyTypes(*policyTypesPtr)
 	if err != nil {
-		fmt.Println("Error checking permissions")
-		logs.Println(err)
+		fmt.Println("Error parsing policy types")
+		fmt.Println(err)
+		return
+	}
+	if *effectivePoliciesPtr && len(policyTypes) == 0 {
+		fmt.Println("The -effective-policies flag requires at least one policy type to be given with -policy-types")
+		return
+	}
+	tagKeys := splitList(*showTagsPtr)
+	if *jsonPtr && sameFile(*inputPtr, *outputPtr) {
+		fmt.Printf("The JSON output would overwrite the input file %s, choose another file with -o or use -include-json=false\n", *inputPtr)
+		return
+	}
+	switch *formatPtr {
+	case "tree", "dot", "mermaid", "markdown", "csv", "tsv":
+	default:
+		fmt.Printf("Unknown format %s, expected tree, dot, mermaid, markdown, csv or tsv\n", *formatPtr)
+		return
+	}
+	mermaidDiagram, err := mermaid.ParseDiagram(*mermaidDiagramPtr)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	sortOrder, err := generation.ParseSortOrder(*sortPtr)
+	if err != nil {
+		fmt.Println(err)
 		return
 	}
+	csvColumns, err := csv.ParseColumns(*csvColumnsPtr)
+	if err != nil {
+		fmt.Println("Error parsing CSV columns")
+		fmt.Println(err)
+		return
+	}
+	csvDelimiter := []rune(*csvDelimiterPtr)
+	if len(csvDelimiter) != 1 {
+		fmt.Println("The -csv-delimiter flag must be a single character")
+		return
+	}
+	if *formatPtr == "tsv" {
+		csvDelimiter = []rune{'\t'}
+	}
+	var colour bool
+	switch *colourPtr {
+	case "auto":
+		colour = cli.ColourSupported(os.Stdout)
+	case "always":
+		colour = true
+	case "never":
+		colour = false
+	default:
+		fmt.Printf("Unknown colour mode %s, expected auto, always or never\n", *colourPtr)
+		return
+	}
+	connectors := cli.UnicodeConnectors
+	if *asciiPtr {
+		connectors = cli.ASCIIConnectors
+	}
+
+	// STAGE 2: Set up the logging
+	ll := os.Getenv("LOGS_ENABLED")
+	setupLogging(ll)
 	logs.Println("Output file:", *outputPtr)
 
-	// STAGE 3: Run the main logic of the application to generate the data
-	// structure
-	tree, err := generation.GenerateStructure(ctx, cfg)
+	// STAGE 3: Get the data structure, either from a saved snapshot or by
+	// running the main logic of the application against AWS
+	org, err := getOrganization(*inputPtr, generation.Options{
+		Parallelism: *parallelismPtr,
+		Retry: generation.RetryPolicy{
+			MaxAttempts: *maxAttemptsPtr,
+			BaseDelay:   *retryBaseDelayPtr,
+			MaxDelay:    *retryMaxDelayPtr,
+		},
+		PolicyTypes:                    policyTypes,
+		EffectivePolicies:              *effectivePoliciesPtr,
+		IncludeTags:                    *includeTagsPtr || len(tagKeys) > 0,
+		IncludeDelegatedAdministrators: *delegatedAdminsPtr,
+	})
 	if err != nil {
-		fmt.Println("Error generating structure")
-		logs.Println(err)
 		return
 	}
 	if *removeSuspendedAccountsPtr {
-		tree = tree.RemoveSuspendedAccounts()
+		org.Root = org.Root.RemoveSuspendedAccounts()
 	}
+	org.Root = org.Root.Sort(sortOrder)
 
 	// STAGE 4: Determine the output format and output the data structure
 	// If no output format is specified, exit
-	if !*visualPtr && !*jsonPtr {
+	if !*visualPtr && !*jsonPtr && *htmlPtr == "" {
 		fmt.Println("No output format specified, exiting...")
 		return
 	}
 	// If the visual output format is specified, display the data structure on
-	// the CLI
+	// the CLI in the chosen format
 	if *visualPtr {
-		cli.Display(tree)
+		switch *formatPtr {
+		case "dot":
+			fmt.Print(string(dot.Create(org, dot.Options{
+				IncludeAccounts: *showAccountsPtr,
+				Clusters:        *dotClustersPtr,
+				MaxDepth:        *maxDepthPtr,
+			})))
+		case "mermaid":
+			fmt.Print(string(mermaid.Create(org, mermaid.Options{
+				Diagram:           mermaidDiagram,
+				IncludeAccounts:   *showAccountsPtr,
+				IncludeAccountIds: *showAccountIdsPtr,
+				MaxDepth:          *maxDepthPtr,
+			})))
+		case "markdown":
+			fmt.Print(string(markdown.Create(org, markdown.Options{
+				MaxDepth: *maxDepthPtr,
+			})))
+		case "csv", "tsv":
+			table, err := csv.Create(org, csv.Options{
+				Columns:   csvColumns,
+				Delimiter: csvDelimiter[0],
+			})
+			if err != nil {
+				fmt.Println("Error generating the account inventory")
+				logs.Println(err)
+				return
+			}
+			fmt.Print(string(table))
+		default:
+			cli.Display(org, cli.Options{
+				Detailed:     true,
+				TagKeys:      tagKeys,
+				Totals:       *showTotalsPtr,
+				ShowAccounts: *showAccountsPtr,
+				MaxDepth:     *maxDepthPtr,
+				Colour:       colour,
+				Connectors:   connectors,
+			})
+		}
 	}
 
 	// If the JSON output format is specified, output the data structure to a
 	// JSON file with the given name
 	if *jsonPtr {
-		jsonTree, err := json.Create(tree)
+		jsonTree, err := json.Create(org)
 		if err != nil {
 			fmt.Println("Error generating JSON")
 			logs.Println(err)
@@ -155,4 +513,21 @@ func main() {
 			return
 		}
 	}
+
+	// If an HTML file is specified, output the data structure along with the
+	// viewer to it
+	if *htmlPtr != "" {
+		htmlPage, err := html.Create(org)
+		if err != nil {
+			fmt.Println("Error generating HTML")
+			logs.Println(err)
+			return
+		}
+		err = html.OutputToFile(htmlPage, *htmlPtr)
+		if err != nil {
+			fmt.Println("Error outputting HTML to file")
+			logs.Println(err)
+			return
+		}
+	}
 }
@@ -2,8 +2,10 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -56,6 +58,13 @@ func TestPrintlnLogging(t *testing.T) {
 	require.Equal(t, "", output2, "Expected output to be ''")
 }
 
+// TestSplitList tests the splitList function trims the parts of a comma
+// separated list and drops any empty ones.
+func TestSplitList(t *testing.T) {
+	require.Equal(t, []string{}, splitList(""), "Expected no parts")
+	require.Equal(t, []string{"owner", "env"}, splitList(" owner, ,env,"), "Expected trimmed parts")
+}
+
 // captureOutput is a helper function to capture the output of a function.
 // This is used to test the output of the display functions.
 func captureOutput(f func()) string {
@@ -78,3 +87,37 @@ func captureOutput(f func()) string {
 	// Return the output.
 	return buf.String()
 }
+
+// TestSameFile tests that the JSON output is only treated as the input file if
+// they are the same existing file.
+func TestSameFile(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "output.json")
+	require.NoError(t, os.WriteFile(input, []byte("{}"), 0o644))
+
+	require.True(t, sameFile(input, input))
+	require.True(t, sameFile(input, filepath.Join(dir, ".", "output.json")))
+	require.False(t, sameFile(input, filepath.Join(dir, "other.json")))
+	require.False(t, sameFile("", input))
+	require.False(t, sameFile(input, ""))
+}
+
+// TestMainRefusesToOverwriteInput tests that a snapshot given with -input isn't
+// overwritten by the JSON output, which defaults to the same name.
+func TestMainRefusesToOverwriteInput(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "output.json")
+	snapshot := []byte(`{"root":{"id":"r-1234","name":"Root","accounts":[{"Id":"111111111111","Status":"SUSPENDED"}],"children":[]}}`)
+	require.NoError(t, os.WriteFile(input, snapshot, 0o644))
+
+	originalArgs, originalFlags := os.Args, flag.CommandLine
+	defer func() { os.Args, flag.CommandLine = originalArgs, originalFlags }()
+	flag.CommandLine = flag.NewFlagSet("aws-organizations-visualiser", flag.ContinueOnError)
+	os.Args = []string{"aws-organizations-visualiser", "-input", input, "-o", input, "-remove-suspended-accounts"}
+
+	output := captureOutput(main)
+	require.Contains(t, output, "would overwrite the input file")
+	written, err := os.ReadFile(input)
+	require.NoError(t, err)
+	require.Equal(t, snapshot, written, "The input file was overwritten")
+}
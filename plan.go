@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/display/json"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/layout"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/plan"
+)
+
+// runPlan is the entry point of the plan command, it works out the steps
+// needed to make the organization, either live or from a snapshot, match the
+// desired layout and outputs them. It never makes any changes.
+func runPlan(args []string) {
+	// STAGE 1: Sort out the input flags
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+	inputPtr := flags.String("input", "", "A JSON file previously written by this application to plan from instead of querying AWS")
+	jsonPtr := flags.Bool("include-json", true, "Include the JSON plan in the output")
+	visualPtr := flags.Bool("include-visual", true, "Include the human readable plan in the output")
+	outputPtr := flags.String("o", "plan.json", "The output file for the JSON plan")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aws-organizations-visualiser plan [flags] layout.yaml")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return
+	}
+
+	// STAGE 2: Set up the logging
+	ll := os.Getenv("LOGS_ENABLED")
+	setupLogging(ll)
+
+	// STAGE 3: Get the desired layout and the organization
+	desired, err := layout.ReadFromFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println("Error reading layout")
+		logs.Println(err)
+		return
+	}
+	org, err := getOrganization(*inputPtr, generation.Options{})
+	if err != nil {
+		return
+	}
+
+	// STAGE 4: Generate the plan and output it
+	p := plan.Generate(desired, org.Root)
+	if *visualPtr {
+		fmt.Print(p.Report())
+	}
+	if *jsonPtr {
+		jsonPlan, err := p.ToJSON()
+		if err != nil {
+			fmt.Println("Error generating JSON")
+			logs.Println(err)
+			return
+		}
+		err = json.OutputToFile(jsonPlan, *outputPtr)
+		if err != nil {
+			fmt.Println("Error outputting JSON to file")
+			logs.Println(err)
+			return
+		}
+	}
+	if *visualPtr && len(p.Steps) > 0 {
+		fmt.Println("This is a dry run, no changes have been made.")
+	}
+}
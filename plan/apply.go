@@ -0,0 +1,214 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// ErrDeclined is returned by Apply when a step isn't confirmed, the steps
+// before it have been completed and the run can be resumed later.
+var ErrDeclined = errors.New("step declined")
+
+// organizationsAPI is every Organizations call needed to apply a plan.
+type organizationsAPI interface {
+	CreateOrganizationalUnit
+	MoveAccount
+	DeleteOrganizationalUnit
+	ListOrganizationalUnitsForParent
+}
+
+// ApplyOptions holds the settings that control how a plan is applied.
+type ApplyOptions struct {
+	// Confirm is called before each step is run, with the step's position in
+	// the plan starting at 1. Returning false stops the run with ErrDeclined.
+	// Every step is run if it is nil.
+	Confirm func(position int, step Step) bool
+	// Completed is called after each step has been run. It is optional.
+	Completed func(position int, step Step)
+	// Journal records the steps as they are completed. Steps already in the
+	// journal are skipped, so that a failed run can be resumed. No progress is
+	// kept if it is nil.
+	Journal *Journal
+	// Retry controls how each call is retried when AWS throttles it. Any unset
+	// fields are taken from the generation.DefaultRetryPolicy.
+	Retry generation.RetryPolicy
+}
+
+// Apply runs each step of the plan against the organization in order,
+// stopping at the first step that fails or isn't confirmed.
+func Apply(ctx context.Context, orgClient *organizations.Client, p *Plan, opts ApplyOptions) error {
+	return apply(ctx, orgClient, p, opts)
+}
+
+// apply is the implementation of Apply against any client that provides the
+// Organizations calls, so that it can be tested with mocks.
+func apply(ctx context.Context, api organizationsAPI, p *Plan, opts ApplyOptions) error {
+	journal := opts.Journal
+	if journal == nil {
+		journal = &Journal{}
+	}
+
+	// The IDs of the OUs created by the plan, keyed by their paths, so that
+	// later steps can refer to them.
+	created := make(map[string]string)
+
+	for i, step := range p.Steps {
+		position := i + 1
+
+		// Skip the steps that were completed by an earlier run, checking that
+		// the journal belongs to this plan.
+		if entry, ok := journal.completed(position); ok {
+			if entry.Action != step.Action || entry.Name != step.Name {
+				return fmt.Errorf("the journal doesn't match the plan at step %d: journal has %s %s", position, entry.Action, entry.Name)
+			}
+			if step.Action == ActionCreateOU {
+				created[step.Path] = entry.Id
+			}
+			continue
+		}
+
+		if opts.Confirm != nil && !opts.Confirm(position, step) {
+			return ErrDeclined
+		}
+		id, err := applyStep(ctx, api, opts.Retry, step, created)
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", step, err)
+		}
+		err = journal.record(JournalEntry{
+			Step:        position,
+			Action:      step.Action,
+			Name:        step.Name,
+			Id:          id,
+			CompletedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if opts.Completed != nil {
+			opts.Completed(position, step)
+		}
+	}
+	return nil
+}
+
+// applyStep runs a single step of the plan, returning the ID of the OU it
+// creates or the account or OU it changes.
+//
+// A step may have been completed by an earlier run that failed before it was
+// recorded in the journal, for example if the call timed out after AWS had
+// made the change. An OU that already exists is looked up rather than created
+// again, and an account that is already in its destination is left where it
+// is, and an OU that no longer exists is treated as deleted, so that the run
+// can be resumed.
+func applyStep(ctx context.Context, api organizationsAPI, retry generation.RetryPolicy, step Step, created map[string]string) (string, error) {
+	switch step.Action {
+	case ActionCreateOU:
+		parentId, err := resolve(step.ParentId, step.ParentPath, created)
+		if err != nil {
+			return "", err
+		}
+		output, err := generation.WithRetry(ctx, retry, func() (*organizations.CreateOrganizationalUnitOutput, error) {
+			return api.CreateOrganizationalUnit(ctx, &organizations.CreateOrganizationalUnitInput{
+				ParentId: aws.String(parentId),
+				Name:     aws.String(step.Name),
+			})
+		})
+		var duplicate *types.DuplicateOrganizationalUnitException
+		if errors.As(err, &duplicate) {
+			id, findErr := findOU(ctx, api, retry, parentId, step.Name)
+			if findErr != nil {
+				return "", fmt.Errorf("%w, and looking it up failed: %v", err, findErr)
+			}
+			created[step.Path] = id
+			return id, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if output.OrganizationalUnit == nil {
+			return "", fmt.Errorf("no OU returned")
+		}
+		id := aws.ToString(output.OrganizationalUnit.Id)
+		created[step.Path] = id
+		return id, nil
+
+	case ActionMoveAccount:
+		destinationId, err := resolve(step.DestinationId, step.DestinationPath, created)
+		if err != nil {
+			return "", err
+		}
+		_, err = generation.WithRetry(ctx, retry, func() (*organizations.MoveAccountOutput, error) {
+			return api.MoveAccount(ctx, &organizations.MoveAccountInput{
+				AccountId:           aws.String(step.Id),
+				SourceParentId:      aws.String(step.SourceId),
+				DestinationParentId: aws.String(destinationId),
+			})
+		})
+		var duplicate *types.DuplicateAccountException
+		if errors.As(err, &duplicate) {
+			return step.Id, nil
+		}
+		return step.Id, err
+
+	case ActionDeleteOU:
+		_, err := generation.WithRetry(ctx, retry, func() (*organizations.DeleteOrganizationalUnitOutput, error) {
+			return api.DeleteOrganizationalUnit(ctx, &organizations.DeleteOrganizationalUnitInput{
+				OrganizationalUnitId: aws.String(step.Id),
+			})
+		})
+		var notFound *types.OrganizationalUnitNotFoundException
+		if errors.As(err, &notFound) {
+			return step.Id, nil
+		}
+		return step.Id, err
+	}
+	return "", fmt.Errorf("unknown action %q", step.Action)
+}
+
+// findOU returns the ID of the OU with the given name directly below the
+// parent, following the NextToken of each response until it is found or every
+// page has been read.
+func findOU(ctx context.Context, api ListOrganizationalUnitsForParent, retry generation.RetryPolicy, parentId, name string) (string, error) {
+	var nextToken *string
+	for {
+		page, err := generation.WithRetry(ctx, retry, func() (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+			return api.ListOrganizationalUnitsForParent(ctx, &organizations.ListOrganizationalUnitsForParentInput{
+				ParentId:  aws.String(parentId),
+				NextToken: nextToken,
+			})
+		})
+		if err != nil {
+			return "", err
+		}
+		for _, ou := range page.OrganizationalUnits {
+			if aws.ToString(ou.Name) == name {
+				return aws.ToString(ou.Id), nil
+			}
+		}
+
+		// Stop once the API reports there are no more pages.
+		if page.NextToken == nil || *page.NextToken == "" {
+			return "", fmt.Errorf("no OU named %s in %s", name, parentId)
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// resolve returns the ID of an OU, looking it up by its path in the OUs
+// created by the plan if it wasn't known when the plan was generated.
+func resolve(id, path string, created map[string]string) (string, error) {
+	if id != "" {
+		return id, nil
+	}
+	if id, ok := created[path]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("OU %s hasn't been created", path)
+}
@@ -0,0 +1,370 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+)
+
+// organizationsMock is a mock of every Organizations call needed to apply a
+// plan.
+type organizationsMock struct {
+	CreateOrganizationalUnitMock
+	MoveAccountMock
+	DeleteOrganizationalUnitMock
+	ListOrganizationalUnitsForParentMock
+}
+
+// newTestOrganizationsMock creates an organizationsMock that records each call
+// made to it, creating OUs with IDs based on their names. Calls for the failing
+// account or OU return an error. Listing the OUs in a parent returns an OU for
+// each of the names in the test plan, over two pages.
+func newTestOrganizationsMock(calls *[]string, failing string) *organizationsMock {
+	return &organizationsMock{
+		CreateOrganizationalUnitMock: CreateOrganizationalUnitMock{
+			CreateOrganizationalUnitFunc: func(
+				ctx context.Context,
+				params *organizations.CreateOrganizationalUnitInput,
+				optFns ...func(*organizations.Options),
+			) (
+				*organizations.CreateOrganizationalUnitOutput,
+				error,
+			) {
+				*calls = append(*calls, fmt.Sprintf("create %s in %s", *params.Name, *params.ParentId))
+				if *params.Name == failing {
+					return nil, fmt.Errorf("Testing Error")
+				}
+				return &organizations.CreateOrganizationalUnitOutput{
+					OrganizationalUnit: &types.OrganizationalUnit{Id: aws.String("ou-" + *params.Name)},
+				}, nil
+			},
+		},
+		MoveAccountMock: MoveAccountMock{
+			MoveAccountFunc: func(
+				ctx context.Context,
+				params *organizations.MoveAccountInput,
+				optFns ...func(*organizations.Options),
+			) (
+				*organizations.MoveAccountOutput,
+				error,
+			) {
+				*calls = append(*calls, fmt.Sprintf("move %s from %s to %s", *params.AccountId, *params.SourceParentId, *params.DestinationParentId))
+				if *params.AccountId == failing {
+					return nil, fmt.Errorf("Testing Error")
+				}
+				return &organizations.MoveAccountOutput{}, nil
+			},
+		},
+		DeleteOrganizationalUnitMock: DeleteOrganizationalUnitMock{
+			DeleteOrganizationalUnitFunc: func(
+				ctx context.Context,
+				params *organizations.DeleteOrganizationalUnitInput,
+				optFns ...func(*organizations.Options),
+			) (
+				*organizations.DeleteOrganizationalUnitOutput,
+				error,
+			) {
+				*calls = append(*calls, fmt.Sprintf("delete %s", *params.OrganizationalUnitId))
+				if *params.OrganizationalUnitId == failing {
+					return nil, fmt.Errorf("Testing Error")
+				}
+				return &organizations.DeleteOrganizationalUnitOutput{}, nil
+			},
+		},
+		ListOrganizationalUnitsForParentMock: ListOrganizationalUnitsForParentMock{
+			ListOrganizationalUnitsForParentFunc: func(
+				ctx context.Context,
+				params *organizations.ListOrganizationalUnitsForParentInput,
+				optFns ...func(*organizations.Options),
+			) (
+				*organizations.ListOrganizationalUnitsForParentOutput,
+				error,
+			) {
+				*calls = append(*calls, fmt.Sprintf("list OUs in %s", *params.ParentId))
+				if params.NextToken == nil {
+					return &organizations.ListOrganizationalUnitsForParentOutput{
+						OrganizationalUnits: []types.OrganizationalUnit{
+							{Id: aws.String("ou-Other"), Name: aws.String("Other")},
+						},
+						NextToken: aws.String("page-2"),
+					}, nil
+				}
+				return &organizations.ListOrganizationalUnitsForParentOutput{
+					OrganizationalUnits: []types.OrganizationalUnit{
+						{Id: aws.String("ou-Dev"), Name: aws.String("Dev")},
+						{Id: aws.String("ou-Team"), Name: aws.String("Team")},
+					},
+				}, nil
+			},
+		},
+	}
+}
+
+// testRetryPolicy retries throttled calls quickly so the tests stay fast.
+var testRetryPolicy = generation.RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    2 * time.Millisecond,
+}
+
+// testPlan is the plan applied in the tests below, the second OU and the
+// account moved into it depend on the first OU it creates.
+var testPlan = &Plan{
+	Steps: []Step{
+		{Action: ActionCreateOU, Name: "Dev", Path: "Root/Workloads/Dev", ParentPath: "Root/Workloads", ParentId: "ou-1"},
+		{Action: ActionCreateOU, Name: "Team", Path: "Root/Workloads/Dev/Team", ParentPath: "Root/Workloads/Dev"},
+		{Action: ActionMoveAccount, Id: "222222222222", Name: "Prod", SourcePath: "Root", SourceId: "r-1234", DestinationPath: "Root/Workloads/Dev/Team"},
+		{Action: ActionDeleteOU, Id: "ou-2", Name: "Legacy", Path: "Root/Legacy"},
+	},
+}
+
+// TestApply tests that each step is run in order, using the IDs of the OUs
+// created earlier in the plan.
+func TestApply(t *testing.T) {
+	var calls []string
+	var completed []int
+	err := apply(context.Background(), newTestOrganizationsMock(&calls, ""), testPlan, ApplyOptions{
+		Completed: func(position int, step Step) {
+			completed = append(completed, position)
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"create Dev in ou-1",
+		"create Team in ou-Dev",
+		"move 222222222222 from r-1234 to ou-Team",
+		"delete ou-2",
+	}, calls)
+	require.Equal(t, []int{1, 2, 3, 4}, completed)
+}
+
+// TestApplyConfirm tests that the run stops at the first step that isn't
+// confirmed.
+func TestApplyConfirm(t *testing.T) {
+	var calls []string
+	var asked []int
+	err := apply(context.Background(), newTestOrganizationsMock(&calls, ""), testPlan, ApplyOptions{
+		Confirm: func(position int, step Step) bool {
+			asked = append(asked, position)
+			return step.Action == ActionCreateOU
+		},
+	})
+	require.ErrorIs(t, err, ErrDeclined)
+	require.Equal(t, []int{1, 2, 3}, asked)
+	require.Equal(t, []string{"create Dev in ou-1", "create Team in ou-Dev"}, calls)
+}
+
+// TestApplyResume tests that a failed run can be resumed from its journal,
+// skipping the steps that were completed.
+func TestApplyResume(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "plan.journal")
+	journal, err := OpenJournal(filename)
+	require.NoError(t, err)
+
+	// The first run fails when moving the account.
+	var calls []string
+	err = apply(context.Background(), newTestOrganizationsMock(&calls, "222222222222"), testPlan, ApplyOptions{Journal: journal})
+	require.ErrorContains(t, err, "Testing Error")
+	require.ErrorContains(t, err, "move account Prod (222222222222)")
+	require.Len(t, calls, 3)
+
+	// The second run reads the journal and picks up from the failed step,
+	// using the ID of the OU created by the first run.
+	journal, err = OpenJournal(filename)
+	require.NoError(t, err)
+	require.Len(t, journal.Entries, 2)
+	require.Equal(t, "ou-Team", journal.Entries[1].Id)
+
+	calls = nil
+	err = apply(context.Background(), newTestOrganizationsMock(&calls, ""), testPlan, ApplyOptions{Journal: journal})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"move 222222222222 from r-1234 to ou-Team",
+		"delete ou-2",
+	}, calls)
+
+	// A journal from a different plan is rejected.
+	other := &Plan{Steps: []Step{{Action: ActionDeleteOU, Id: "ou-3", Name: "Other"}}}
+	calls = nil
+	err = apply(context.Background(), newTestOrganizationsMock(&calls, ""), other, ApplyOptions{Journal: journal})
+	require.ErrorContains(t, err, "doesn't match the plan")
+	require.Empty(t, calls)
+}
+
+// TestApplyResumeCreatedOU tests that a run can be resumed when an OU was
+// created by an earlier run that failed before recording it, looking up the
+// existing OU rather than failing to create it again.
+func TestApplyResumeCreatedOU(t *testing.T) {
+	var calls []string
+	api := newTestOrganizationsMock(&calls, "")
+	create := api.CreateOrganizationalUnitFunc
+	api.CreateOrganizationalUnitFunc = func(
+		ctx context.Context,
+		params *organizations.CreateOrganizationalUnitInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.CreateOrganizationalUnitOutput,
+		error,
+	) {
+		if *params.Name == "Dev" {
+			calls = append(calls, fmt.Sprintf("create %s in %s", *params.Name, *params.ParentId))
+			return nil, &types.DuplicateOrganizationalUnitException{Message: aws.String("already exists")}
+		}
+		return create(ctx, params, optFns...)
+	}
+
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "plan.journal"))
+	require.NoError(t, err)
+	err = apply(context.Background(), api, testPlan, ApplyOptions{Journal: journal, Retry: testRetryPolicy})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"create Dev in ou-1",
+		"list OUs in ou-1",
+		"list OUs in ou-1",
+		"create Team in ou-Dev",
+		"move 222222222222 from r-1234 to ou-Team",
+		"delete ou-2",
+	}, calls)
+	require.Equal(t, "ou-Dev", journal.Entries[0].Id)
+
+	// The original error is returned if the OU can't be found.
+	p := &Plan{Steps: []Step{{Action: ActionCreateOU, Name: "Dev", Path: "Root/Dev", ParentId: "r-1234"}}}
+	api.ListOrganizationalUnitsForParentFunc = func(
+		ctx context.Context,
+		params *organizations.ListOrganizationalUnitsForParentInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListOrganizationalUnitsForParentOutput,
+		error,
+	) {
+		return &organizations.ListOrganizationalUnitsForParentOutput{}, nil
+	}
+	err = apply(context.Background(), api, p, ApplyOptions{Retry: testRetryPolicy})
+	var duplicate *types.DuplicateOrganizationalUnitException
+	require.ErrorAs(t, err, &duplicate)
+	require.ErrorContains(t, err, "no OU named Dev in r-1234")
+}
+
+// TestApplyResumeMovedAccount tests that a run can be resumed when an account
+// was moved by an earlier run that failed before recording it, treating the
+// move as done.
+func TestApplyResumeMovedAccount(t *testing.T) {
+	var calls []string
+	api := newTestOrganizationsMock(&calls, "")
+	api.MoveAccountFunc = func(
+		ctx context.Context,
+		params *organizations.MoveAccountInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.MoveAccountOutput,
+		error,
+	) {
+		calls = append(calls, fmt.Sprintf("move %s", *params.AccountId))
+		return nil, &types.DuplicateAccountException{Message: aws.String("already moved")}
+	}
+
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "plan.journal"))
+	require.NoError(t, err)
+	err = apply(context.Background(), api, testPlan, ApplyOptions{Journal: journal, Retry: testRetryPolicy})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"create Dev in ou-1",
+		"create Team in ou-Dev",
+		"move 222222222222",
+		"delete ou-2",
+	}, calls)
+	require.Len(t, journal.Entries, 4)
+	require.Equal(t, "222222222222", journal.Entries[2].Id)
+}
+
+// TestApplyResumeDeletedOU tests that a run can be resumed when an OU was
+// deleted by an earlier run that failed before recording it, treating the
+// delete as done.
+func TestApplyResumeDeletedOU(t *testing.T) {
+	var calls []string
+	api := newTestOrganizationsMock(&calls, "")
+	api.DeleteOrganizationalUnitFunc = func(
+		ctx context.Context,
+		params *organizations.DeleteOrganizationalUnitInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DeleteOrganizationalUnitOutput,
+		error,
+	) {
+		calls = append(calls, fmt.Sprintf("delete %s", *params.OrganizationalUnitId))
+		return nil, &types.OrganizationalUnitNotFoundException{Message: aws.String("not found")}
+	}
+
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "plan.journal"))
+	require.NoError(t, err)
+	err = apply(context.Background(), api, testPlan, ApplyOptions{Journal: journal, Retry: testRetryPolicy})
+	require.NoError(t, err)
+	require.Equal(t, "delete ou-2", calls[len(calls)-1])
+	require.Len(t, journal.Entries, 4)
+	require.Equal(t, "ou-2", journal.Entries[3].Id)
+}
+
+// TestApplyRetry tests that throttled calls are retried with the policy.
+func TestApplyRetry(t *testing.T) {
+	var calls []string
+	api := newTestOrganizationsMock(&calls, "")
+	move := api.MoveAccountFunc
+	throttled := 0
+	api.MoveAccountFunc = func(
+		ctx context.Context,
+		params *organizations.MoveAccountInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.MoveAccountOutput,
+		error,
+	) {
+		if throttled < 2 {
+			throttled++
+			return nil, &smithy.GenericAPIError{Code: "TooManyRequestsException"}
+		}
+		return move(ctx, params, optFns...)
+	}
+
+	err := apply(context.Background(), api, testPlan, ApplyOptions{Retry: testRetryPolicy})
+	require.NoError(t, err)
+	require.Equal(t, 2, throttled)
+	require.Contains(t, calls, "move 222222222222 from r-1234 to ou-Team")
+
+	// The call fails once the policy runs out of attempts.
+	throttled = -10
+	calls = nil
+	err = apply(context.Background(), api, testPlan, ApplyOptions{Retry: testRetryPolicy})
+	require.ErrorContains(t, err, "throttled after 3 attempts")
+}
+
+// TestApplyUnknownParent tests that a step referring to an OU that the plan
+// hasn't created fails.
+func TestApplyUnknownParent(t *testing.T) {
+	var calls []string
+	p := &Plan{Steps: []Step{{Action: ActionCreateOU, Name: "Team", Path: "Root/Dev/Team", ParentPath: "Root/Dev"}}}
+	err := apply(context.Background(), newTestOrganizationsMock(&calls, ""), p, ApplyOptions{})
+	require.ErrorContains(t, err, "OU Root/Dev hasn't been created")
+	require.Empty(t, calls)
+}
+
+// TestOpenJournalInvalid tests that a journal that can't be parsed is reported.
+func TestOpenJournalInvalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "plan.journal")
+	journal, err := OpenJournal(filename)
+	require.NoError(t, err)
+	require.NoError(t, journal.record(JournalEntry{Step: 1}))
+
+	require.NoError(t, os.WriteFile(filename, []byte("not json"), 0o600))
+	_, err = OpenJournal(filename)
+	require.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// --- Journal -----------------------------------------------------------------
+// Journal is a struct that records the steps of a plan as they are completed,
+// saving itself to a file after each one so that a run that fails part way
+// through can be resumed from where it stopped.
+type Journal struct {
+	filename string
+	Entries  []JournalEntry `json:"entries"`
+}
+
+// --- JournalEntry ------------------------------------------------------------
+// JournalEntry is a struct that represents a completed step of the plan.
+type JournalEntry struct {
+	// Step is the position of the step in the plan, starting at 1.
+	Step   int    `json:"step"`
+	Action Action `json:"action"`
+	Name   string `json:"name"`
+	// Id is the ID of the OU created by the step, or of the account moved or OU
+	// deleted.
+	Id          string    `json:"id"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// OpenJournal is a function that reads the journal from the given file, or
+// starts a new one if the file doesn't exist yet.
+func OpenJournal(filename string) (*Journal, error) {
+	journal := &Journal{filename: filename, Entries: make([]JournalEntry, 0)}
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, journal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the journal: %w", err)
+	}
+	return journal, nil
+}
+
+// completed returns the entry for the step at the given position, if the step
+// has already been completed.
+func (j *Journal) completed(step int) (JournalEntry, bool) {
+	for _, entry := range j.Entries {
+		if entry.Step == step {
+			return entry, true
+		}
+	}
+	return JournalEntry{}, false
+}
+
+// record adds the completed step to the journal and saves it to its file.
+func (j *Journal) record(entry JournalEntry) error {
+	j.Entries = append(j.Entries, entry)
+	if j.filename == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so the journal is never left half
+	// written.
+	tmp := j.filename + ".tmp"
+	err = os.WriteFile(tmp, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to save the journal: %w", err)
+	}
+	err = os.Rename(tmp, j.filename)
+	if err != nil {
+		return fmt.Errorf("failed to save the journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package plan
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+)
+
+// --- CreateOrganizationalUnit ------------------------------------------------
+// CreateOrganizationalUnit is an interface for the organizations
+// CreateOrganizationalUnit function in the AWS SDK that allows for mocking.
+type CreateOrganizationalUnit interface {
+	CreateOrganizationalUnit(
+		ctx context.Context,
+		params *organizations.CreateOrganizationalUnitInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.CreateOrganizationalUnitOutput,
+		error,
+	)
+}
+
+type CreateOrganizationalUnitMock struct {
+	CreateOrganizationalUnitFunc func(
+		ctx context.Context,
+		params *organizations.CreateOrganizationalUnitInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.CreateOrganizationalUnitOutput,
+		error,
+	)
+}
+
+func (m *CreateOrganizationalUnitMock) CreateOrganizationalUnit(
+	ctx context.Context,
+	params *organizations.CreateOrganizationalUnitInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.CreateOrganizationalUnitOutput,
+	error,
+) {
+	return m.CreateOrganizationalUnitFunc(ctx, params, optFns...)
+}
+
+// --- MoveAccount -------------------------------------------------------------
+// MoveAccount is an interface for the organizations MoveAccount function in the
+// AWS SDK that allows for mocking.
+type MoveAccount interface {
+	MoveAccount(
+		ctx context.Context,
+		params *organizations.MoveAccountInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.MoveAccountOutput,
+		error,
+	)
+}
+
+type MoveAccountMock struct {
+	MoveAccountFunc func(
+		ctx context.Context,
+		params *organizations.MoveAccountInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.MoveAccountOutput,
+		error,
+	)
+}
+
+func (m *MoveAccountMock) MoveAccount(
+	ctx context.Context,
+	params *organizations.MoveAccountInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.MoveAccountOutput,
+	error,
+) {
+	return m.MoveAccountFunc(ctx, params, optFns...)
+}
+
+// --- DeleteOrganizationalUnit ------------------------------------------------
+// DeleteOrganizationalUnit is an interface for the organizations
+// DeleteOrganizationalUnit function in the AWS SDK that allows for mocking.
+type DeleteOrganizationalUnit interface {
+	DeleteOrganizationalUnit(
+		ctx context.Context,
+		params *organizations.DeleteOrganizationalUnitInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DeleteOrganizationalUnitOutput,
+		error,
+	)
+}
+
+type DeleteOrganizationalUnitMock struct {
+	DeleteOrganizationalUnitFunc func(
+		ctx context.Context,
+		params *organizations.DeleteOrganizationalUnitInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.DeleteOrganizationalUnitOutput,
+		error,
+	)
+}
+
+func (m *DeleteOrganizationalUnitMock) DeleteOrganizationalUnit(
+	ctx context.Context,
+	params *organizations.DeleteOrganizationalUnitInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.DeleteOrganizationalUnitOutput,
+	error,
+) {
+	return m.DeleteOrganizationalUnitFunc(ctx, params, optFns...)
+}
+
+// --- ListOrganizationalUnitsForParent ----------------------------------------
+// ListOrganizationalUnitsForParent is an interface for the organizations
+// ListOrganizationalUnitsForParent function in the AWS SDK that allows for
+// mocking.
+type ListOrganizationalUnitsForParent interface {
+	ListOrganizationalUnitsForParent(
+		ctx context.Context,
+		params *organizations.ListOrganizationalUnitsForParentInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListOrganizationalUnitsForParentOutput,
+		error,
+	)
+}
+
+type ListOrganizationalUnitsForParentMock struct {
+	ListOrganizationalUnitsForParentFunc func(
+		ctx context.Context,
+		params *organizations.ListOrganizationalUnitsForParentInput,
+		optFns ...func(*organizations.Options),
+	) (
+		*organizations.ListOrganizationalUnitsForParentOutput,
+		error,
+	)
+}
+
+func (m *ListOrganizationalUnitsForParentMock) ListOrganizationalUnitsForParent(
+	ctx context.Context,
+	params *organizations.ListOrganizationalUnitsForParentInput,
+	optFns ...func(*organizations.Options),
+) (
+	*organizations.ListOrganizationalUnitsForParentOutput,
+	error,
+) {
+	return m.ListOrganizationalUnitsForParentFunc(ctx, params, optFns...)
+}
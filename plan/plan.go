@@ -0,0 +1,258 @@
+// # Plan
+//
+// This package contains the code for planning a restructure of an AWS
+// Organization. It compares the tree generated in the generation package with
+// a desired layout from the layout package and works out the steps needed to
+// make the organization match it, which can then be applied once reviewed.
+//
+// Generating a plan doesn't make any changes, they are only made when the plan
+// is applied. Each step refers to OUs by their path of names from the root as
+// well as their ID when it is already known, so that OUs created earlier in the
+// plan can be used by the later steps.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/layout"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Action is the Organizations operation a step of the plan performs.
+type Action string
+
+const (
+	ActionCreateOU    Action = "CREATE_ORGANIZATIONAL_UNIT"
+	ActionMoveAccount Action = "MOVE_ACCOUNT"
+	ActionDeleteOU    Action = "DELETE_ORGANIZATIONAL_UNIT"
+)
+
+// --- Step --------------------------------------------------------------------
+// Step is a struct that represents a single operation of the plan.
+type Step struct {
+	Action Action `json:"action"`
+	// Id is the ID of the account to move or the OU to delete.
+	Id string `json:"id,omitempty"`
+	// Name is the name of the OU to create or delete, or the account to move.
+	Name string `json:"name"`
+	// Path is the path of the OU to create or delete.
+	Path string `json:"path,omitempty"`
+	// ParentPath and ParentId are the parent of the OU to create, the ID is
+	// empty if the parent is created earlier in the plan.
+	ParentPath string `json:"parentPath,omitempty"`
+	ParentId   string `json:"parentId,omitempty"`
+	// SourcePath and SourceId are the OU the account to move is in.
+	SourcePath string `json:"sourcePath,omitempty"`
+	SourceId   string `json:"sourceId,omitempty"`
+	// DestinationPath and DestinationId are the OU to move the account to, the
+	// ID is empty if the OU is created earlier in the plan.
+	DestinationPath string `json:"destinationPath,omitempty"`
+	DestinationId   string `json:"destinationId,omitempty"`
+}
+
+// String returns a human readable description of the step.
+func (s Step) String() string {
+	switch s.Action {
+	case ActionCreateOU:
+		return fmt.Sprintf("create OU %s in %s", s.Name, s.ParentPath)
+	case ActionMoveAccount:
+		return fmt.Sprintf("move account %s (%s) from %s to %s", s.Name, s.Id, s.SourcePath, s.DestinationPath)
+	case ActionDeleteOU:
+		return fmt.Sprintf("delete OU %s (%s)", s.Path, s.Id)
+	}
+	return fmt.Sprintf("%s %s", s.Action, s.Name)
+}
+
+// --- Plan --------------------------------------------------------------------
+// Plan is a struct that represents the steps needed to make the organization
+// match the desired layout, in the order they must be run.
+type Plan struct {
+	Steps []Step `json:"steps"`
+	// Warnings is anything in the layout that the plan can't achieve.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ToJSON returns a JSON representation of the plan.
+func (p *Plan) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// Report returns a human readable report of the plan, with a numbered line
+// for each step followed by any warnings.
+func (p *Plan) Report() string {
+	var report strings.Builder
+	if len(p.Steps) == 0 {
+		report.WriteString("No changes needed\n")
+	} else {
+		fmt.Fprintf(&report, "Plan: %d step(s)\n", len(p.Steps))
+		for i, step := range p.Steps {
+			fmt.Fprintf(&report, "  %d. %s\n", i+1, step)
+		}
+	}
+	if len(p.Warnings) > 0 {
+		report.WriteString("Warnings:\n")
+		for _, warning := range p.Warnings {
+			fmt.Fprintf(&report, "  - %s\n", warning)
+		}
+	}
+	return report.String()
+}
+
+// Load is a function that parses a plan from its JSON representation, as
+// created by ToJSON.
+func Load(data []byte) (*Plan, error) {
+	p := &Plan{}
+	err := json.Unmarshal(data, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the plan: %w", err)
+	}
+	for i, step := range p.Steps {
+		if step.Action != ActionCreateOU && step.Action != ActionMoveAccount && step.Action != ActionDeleteOU {
+			return nil, fmt.Errorf("failed to parse the plan: step %d has unknown action %q", i+1, step.Action)
+		}
+	}
+	return p, nil
+}
+
+// ReadFromFile is a function that reads a plan from the given JSON file.
+func ReadFromFile(filename string) (*Plan, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
+
+// location is where an account is in the organization.
+type location struct {
+	name     string
+	parentId string
+	path     string
+}
+
+// Generate works out the fewest steps needed to make the organization's tree
+// match the desired layout. The missing OUs are created first, parents before
+// children, then the accounts listed in the layout are moved into place and
+// finally the OUs not in the layout are deleted, children before parents.
+//
+// OUs holding accounts that the layout doesn't list are never deleted, as the
+// accounts would have nowhere to go, and accounts that aren't in the
+// organization can't be created. Both are reported as warnings instead.
+func Generate(desired *layout.Layout, root *generation.OU) *Plan {
+	p := &Plan{Steps: make([]Step, 0)}
+	creates := make([]Step, 0)
+	moves := make([]Step, 0)
+	deletes := make([]Step, 0)
+
+	// Index where each account actually is, and which the layout lists.
+	actual := make(map[string]location)
+	var index func(ou *generation.OU, path string)
+	index = func(ou *generation.OU, path string) {
+		for _, account := range ou.Accounts {
+			actual[aws.ToString(account.Id)] = location{name: aws.ToString(account.Name), parentId: ou.Id, path: path}
+		}
+		for _, child := range ou.Children {
+			index(child, path+"/"+child.Name)
+		}
+	}
+	index(root, desired.Root.Name)
+	listed := make(map[string]bool)
+	var list func(ou layout.OU)
+	list = func(ou layout.OU) {
+		for _, id := range ou.Accounts {
+			listed[id] = true
+		}
+		for _, child := range ou.OUs {
+			list(child)
+		}
+	}
+	list(desired.Root)
+
+	// deleteTree adds the steps to delete the OU and everything below it,
+	// returning false if it can't be deleted.
+	var deleteTree func(ou *generation.OU, path string) bool
+	deleteTree = func(ou *generation.OU, path string) bool {
+		deletable := true
+		for _, child := range ou.Children {
+			if !deleteTree(child, path+"/"+child.Name) {
+				deletable = false
+			}
+		}
+		for _, account := range ou.Accounts {
+			if !listed[aws.ToString(account.Id)] {
+				p.Warnings = append(p.Warnings, fmt.Sprintf("OU %s is not in the layout but can't be deleted as account %s (%s) isn't listed anywhere in the layout", path, aws.ToString(account.Name), aws.ToString(account.Id)))
+				deletable = false
+			}
+		}
+		if deletable {
+			deletes = append(deletes, Step{Action: ActionDeleteOU, Id: ou.Id, Name: ou.Name, Path: path})
+		}
+		return deletable
+	}
+
+	// Walk the layout and the tree together, matching OUs by name.
+	var walk func(want layout.OU, got *generation.OU, path string)
+	walk = func(want layout.OU, got *generation.OU, path string) {
+		gotId := ""
+		if got != nil {
+			gotId = got.Id
+		}
+
+		for _, id := range want.Accounts {
+			at, ok := actual[id]
+			if !ok {
+				p.Warnings = append(p.Warnings, fmt.Sprintf("account %s is not in the organization, expected in %s", id, path))
+			} else if at.path != path {
+				moves = append(moves, Step{
+					Action:          ActionMoveAccount,
+					Id:              id,
+					Name:            at.name,
+					SourcePath:      at.path,
+					SourceId:        at.parentId,
+					DestinationPath: path,
+					DestinationId:   gotId,
+				})
+			}
+		}
+
+		children := make(map[string]*generation.OU)
+		if got != nil {
+			for _, child := range got.Children {
+				children[child.Name] = child
+			}
+		}
+		wanted := make(map[string]bool)
+		for _, child := range want.OUs {
+			wanted[child.Name] = true
+			childPath := path + "/" + child.Name
+			if children[child.Name] == nil {
+				creates = append(creates, Step{
+					Action:     ActionCreateOU,
+					Name:       child.Name,
+					Path:       childPath,
+					ParentPath: path,
+					ParentId:   gotId,
+				})
+			}
+			walk(child, children[child.Name], childPath)
+		}
+
+		if got != nil {
+			for _, child := range got.Children {
+				if !wanted[child.Name] {
+					deleteTree(child, path+"/"+child.Name)
+				}
+			}
+		}
+	}
+	walk(desired.Root, root, desired.Root.Name)
+
+	p.Steps = append(p.Steps, creates...)
+	p.Steps = append(p.Steps, moves...)
+	p.Steps = append(p.Steps, deletes...)
+	return p
+}
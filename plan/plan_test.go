@@ -0,0 +1,157 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CentricaDevOps/aws-organizations-visualiser/generation"
+	"github.com/CentricaDevOps/aws-organizations-visualiser/layout"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTree creates the organization planned against in the tests below.
+func newTestTree() *generation.OU {
+	return &generation.OU{
+		Id:   "r-1234",
+		Name: "Root",
+		Accounts: []generation.Account{
+			{Account: types.Account{Id: aws.String("111111111111"), Name: aws.String("Management")}},
+			{Account: types.Account{Id: aws.String("222222222222"), Name: aws.String("Prod")}},
+		},
+		Children: []*generation.OU{
+			{
+				Id:   "ou-1",
+				Name: "Workloads",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("333333333333"), Name: aws.String("Dev")}},
+				},
+			},
+			{
+				Id:   "ou-2",
+				Name: "Legacy",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("444444444444"), Name: aws.String("Old")}},
+				},
+				Children: []*generation.OU{
+					{Id: "ou-3", Name: "Empty"},
+				},
+			},
+			{
+				Id:   "ou-4",
+				Name: "Unmanaged",
+				Accounts: []generation.Account{
+					{Account: types.Account{Id: aws.String("555555555555"), Name: aws.String("Unlisted")}},
+				},
+			},
+		},
+	}
+}
+
+// TestGenerate tests that the plan creates, moves and deletes in order.
+func TestGenerate(t *testing.T) {
+	desired := &layout.Layout{
+		Root: layout.OU{
+			Name:     "Root",
+			Accounts: []string{"111111111111"},
+			OUs: []layout.OU{
+				{
+					Name: "Workloads",
+					OUs: []layout.OU{
+						{Name: "Prod", Accounts: []string{"222222222222"}},
+						{
+							Name: "Dev",
+							OUs: []layout.OU{
+								{Name: "Team", Accounts: []string{"333333333333"}},
+							},
+						},
+					},
+				},
+				{Name: "Archive", Accounts: []string{"444444444444", "666666666666"}},
+			},
+		},
+	}
+
+	p := Generate(desired, newTestTree())
+	require.Equal(t, []Step{
+		{Action: ActionCreateOU, Name: "Prod", Path: "Root/Workloads/Prod", ParentPath: "Root/Workloads", ParentId: "ou-1"},
+		{Action: ActionCreateOU, Name: "Dev", Path: "Root/Workloads/Dev", ParentPath: "Root/Workloads", ParentId: "ou-1"},
+		{Action: ActionCreateOU, Name: "Team", Path: "Root/Workloads/Dev/Team", ParentPath: "Root/Workloads/Dev"},
+		{Action: ActionCreateOU, Name: "Archive", Path: "Root/Archive", ParentPath: "Root", ParentId: "r-1234"},
+		{Action: ActionMoveAccount, Id: "222222222222", Name: "Prod", SourcePath: "Root", SourceId: "r-1234", DestinationPath: "Root/Workloads/Prod"},
+		{Action: ActionMoveAccount, Id: "333333333333", Name: "Dev", SourcePath: "Root/Workloads", SourceId: "ou-1", DestinationPath: "Root/Workloads/Dev/Team"},
+		{Action: ActionMoveAccount, Id: "444444444444", Name: "Old", SourcePath: "Root/Legacy", SourceId: "ou-2", DestinationPath: "Root/Archive"},
+		{Action: ActionDeleteOU, Id: "ou-3", Name: "Empty", Path: "Root/Legacy/Empty"},
+		{Action: ActionDeleteOU, Id: "ou-2", Name: "Legacy", Path: "Root/Legacy"},
+	}, p.Steps)
+	require.Equal(t, []string{
+		"account 666666666666 is not in the organization, expected in Root/Archive",
+		"OU Root/Unmanaged is not in the layout but can't be deleted as account Unlisted (555555555555) isn't listed anywhere in the layout",
+	}, p.Warnings)
+}
+
+// TestGenerateNoChanges tests that nothing is planned if the organization
+// already matches the layout.
+func TestGenerateNoChanges(t *testing.T) {
+	desired := &layout.Layout{
+		Root: layout.OU{
+			Name: "Root",
+			OUs: []layout.OU{
+				{Name: "Workloads", Accounts: []string{"333333333333"}},
+				{Name: "Legacy", OUs: []layout.OU{{Name: "Empty"}}},
+				{Name: "Unmanaged"},
+			},
+		},
+	}
+
+	p := Generate(desired, newTestTree())
+	require.Empty(t, p.Steps)
+	require.Empty(t, p.Warnings)
+	require.Equal(t, "No changes needed\n", p.Report())
+}
+
+// TestReport tests the human readable report of the plan.
+func TestReport(t *testing.T) {
+	p := &Plan{
+		Steps: []Step{
+			{Action: ActionCreateOU, Name: "Prod", Path: "Root/Workloads/Prod", ParentPath: "Root/Workloads"},
+			{Action: ActionMoveAccount, Id: "222222222222", Name: "Prod", SourcePath: "Root", DestinationPath: "Root/Workloads/Prod"},
+			{Action: ActionDeleteOU, Id: "ou-2", Name: "Legacy", Path: "Root/Legacy"},
+		},
+		Warnings: []string{"account 666666666666 is not in the organization, expected in Root/Archive"},
+	}
+	expectedReport := "" +
+		"Plan: 3 step(s)\n" +
+		"  1. create OU Prod in Root/Workloads\n" +
+		"  2. move account Prod (222222222222) from Root to Root/Workloads/Prod\n" +
+		"  3. delete OU Root/Legacy (ou-2)\n" +
+		"Warnings:\n" +
+		"  - account 666666666666 is not in the organization, expected in Root/Archive\n"
+	require.Equal(t, expectedReport, p.Report())
+}
+
+// TestReadFromFile tests that a plan written as JSON is read back unchanged and
+// that plans with unknown actions are rejected.
+func TestReadFromFile(t *testing.T) {
+	p := &Plan{
+		Steps: []Step{
+			{Action: ActionCreateOU, Name: "Prod", Path: "Root/Prod", ParentPath: "Root", ParentId: "r-1234"},
+			{Action: ActionMoveAccount, Id: "222222222222", Name: "Prod", SourcePath: "Root", SourceId: "r-1234", DestinationPath: "Root/Prod"},
+		},
+	}
+	jsonPlan, err := p.ToJSON()
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(filename, jsonPlan, 0o600))
+
+	loaded, err := ReadFromFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, p, loaded)
+
+	_, err = Load([]byte(`{"steps":[{"action":"CLOSE_ACCOUNT"}]}`))
+	require.Error(t, err)
+	_, err = ReadFromFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunPlan tests that the plan command outputs the steps needed to match
+// the layout without making any changes.
+func TestRunPlan(t *testing.T) {
+	dir := t.TempDir()
+	snapshotFile := filepath.Join(dir, "snapshot.json")
+	layoutFile := filepath.Join(dir, "layout.yaml")
+	outputFile := filepath.Join(dir, "plan.json")
+	require.NoError(t, os.WriteFile(snapshotFile, []byte(`{"id":"o-1234","root":{"id":"r-1234","name":"Root","children":[{"id":"ou-1","name":"Workloads","children":[],"accounts":[]}],"accounts":[]}}`), 0o600))
+	require.NoError(t, os.WriteFile(layoutFile, []byte("root:\n  ous:\n    - name: Sandbox\n"), 0o600))
+
+	output := captureOutput(func() {
+		runPlan([]string{"-o", outputFile, "-input", snapshotFile, layoutFile})
+	})
+	expectedOutput := "" +
+		"Plan: 2 step(s)\n" +
+		"  1. create OU Sandbox in Root\n" +
+		"  2. delete OU Root/Workloads (ou-1)\n" +
+		"This is a dry run, no changes have been made.\n"
+	require.Equal(t, expectedOutput, output)
+
+	jsonPlan, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"steps":[
+		{"action":"CREATE_ORGANIZATIONAL_UNIT","name":"Sandbox","path":"Root/Sandbox","parentPath":"Root","parentId":"r-1234"},
+		{"action":"DELETE_ORGANIZATIONAL_UNIT","id":"ou-1","name":"Workloads","path":"Root/Workloads"}
+	]}`, string(jsonPlan))
+}